@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/bundle"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOut        string
+	bundleRecipients []string
+	bundleIdentity   bool
+	bundleKeyFile    string
+	bundleStrategy   string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the registry and SSH keys to an encrypted bundle",
+	Long: `Packs every local node, access grant and file under ~/.config/entropy/keys into one
+age-encrypted file for moving to another machine. Without --recipient the bundle is
+protected by a passphrase. --recipient takes an age public key (age1...) or an SSH
+public key and may be repeated. Wallet private keys are never exported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var recipients []age.Recipient
+		for _, r := range bundleRecipients {
+			rcpt, err := bundle.ParseRecipient(r)
+			if err != nil {
+				fmt.Printf(mark().Fail+"Invalid recipient %q: %v\n", r, err)
+				return
+			}
+			recipients = append(recipients, rcpt)
+		}
+		if len(recipients) == 0 {
+			pass, err := readPassphrase("Bundle passphrase: ", true)
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			if len(pass) == 0 {
+				fmt.Println(mark().Fail + "An empty passphrase would leave the keys unprotected.")
+				return
+			}
+			rcpt, err := age.NewScryptRecipient(string(pass))
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			recipients = append(recipients, rcpt)
+		}
+
+		b, err := bundle.Build(bundleIdentity)
+		if err != nil {
+			fmt.Printf(mark().Fail+"Failed to read local registry: %v\n", err)
+			return
+		}
+
+		f, err := os.OpenFile(bundleOut, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Printf(mark().Fail+"%v\n", err)
+			return
+		}
+		if err := b.Write(f, recipients...); err != nil {
+			f.Close()
+			os.Remove(bundleOut)
+			fmt.Printf(mark().Fail+"Encryption failed: %v\n", err)
+			return
+		}
+		if err := f.Close(); err != nil {
+			fmt.Printf(mark().Fail+"%v\n", err)
+			return
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"status": "success", "path": bundleOut, "vms": len(b.VMs),
+				"grants": len(b.Grants), "payments": len(b.Payments), "keys": len(b.Keys), "external": b.External,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf(mark().OK+"Exported %d node(s), %d grant(s) and %d key file(s) to %s\n", len(b.VMs), len(b.Grants), len(b.Keys), bundleOut)
+		if len(b.External) > 0 {
+			fmt.Printf(mark().Warn+"Keys outside %s were not included: %s\n", b.KeysDir, strings.Join(b.External, ", "))
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [bundle]",
+	Short: "Restore nodes and SSH keys from an export bundle",
+	Long: `Decrypts a bundle written by 'entropy export' and merges it into the local registry.
+Nodes that collide by ProviderID or alias are handled by --strategy:
+  merge      update the matching node (later lease wins) and rename taken aliases
+  skip       keep local nodes untouched
+  overwrite  replace colliding local nodes
+Key files that differ from a local file of the same name are restored under a new name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		strategy, err := bundle.ParseStrategy(bundleStrategy)
+		if err != nil {
+			fmt.Printf(mark().Fail+"%v\n", err)
+			return
+		}
+
+		var identities []age.Identity
+		if bundleKeyFile != "" {
+			identities, err = bundle.ParseIdentityFile(bundleKeyFile)
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+		} else {
+			locked, err := bundle.IsPassphraseProtected(args[0])
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			if !locked {
+				fmt.Println(mark().Fail + "This bundle is encrypted to a key. Pass it with --identity.")
+				return
+			}
+			pass, err := readPassphrase("Bundle passphrase: ", false)
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			id, err := age.NewScryptIdentity(string(pass))
+			if err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			identities = append(identities, id)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf(mark().Fail+"%v\n", err)
+			return
+		}
+		defer f.Close()
+
+		b, err := bundle.Read(f, identities...)
+		if err != nil {
+			fmt.Printf(mark().Fail+"Could not open bundle: %v\n", err)
+			return
+		}
+
+		rep, err := bundle.Apply(b, strategy)
+		if err != nil {
+			fmt.Printf(mark().Fail+"Import failed, registry unchanged: %v\n", err)
+			return
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(rep, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		list := func(label string, aliases []string) {
+			if len(aliases) > 0 {
+				fmt.Printf("%-10s %s\n", label, strings.Join(aliases, ", "))
+			}
+		}
+		fmt.Printf(icon("📦")+"Bundle from %s (%s strategy)\n\n", b.CreatedAt.Format("2006-01-02 15:04"), strategy)
+		list("ADDED:", rep.Added)
+		list("MERGED:", rep.Merged)
+		list("REPLACED:", rep.Replaced)
+		list("SKIPPED:", rep.Skipped)
+		renamed := make([]string, 0, len(rep.Renamed))
+		for from, to := range rep.Renamed {
+			renamed = append(renamed, from+" → "+to)
+		}
+		sort.Strings(renamed)
+		list("RENAMED:", renamed)
+		fmt.Printf("\n"+mark().OK+"%d key file(s), %d access grant(s) and %d payment(s) restored.\n", rep.Keys, rep.Grants, rep.Payments)
+		if len(rep.RenamedKeys) > 0 {
+			fmt.Printf(mark().Warn+"These keys differed from local files and were restored as *.imported-*: %s\n", strings.Join(rep.RenamedKeys, ", "))
+		}
+		if len(rep.Identity) > 0 {
+			fmt.Printf(icon("🔑")+"Restored identity metadata: %s\n", strings.Join(rep.Identity, ", "))
+		}
+		if rep.UnlinkedEVM != "" {
+			fmt.Printf(mark().Warn+"EVM address %s not restored: no EVM key on this machine. Run 'entropy login evm' to link it.\n", rep.UnlinkedEVM)
+		}
+		if len(rep.External) > 0 {
+			fmt.Printf(mark().Warn+"Copy these nodes' SSH keys manually: %s\n", strings.Join(rep.External, ", "))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().StringVarP(&bundleOut, "out", "o", "entropy-bundle.age", "Bundle file to write")
+	exportCmd.Flags().StringArrayVarP(&bundleRecipients, "recipient", "R", nil, "Encrypt to an age or SSH public key instead of a passphrase")
+	exportCmd.Flags().BoolVar(&bundleIdentity, "include-identity", false, "Include linked wallet addresses and the XMR RPC URL (no private keys)")
+
+	importCmd.Flags().StringVarP(&bundleKeyFile, "identity", "i", "", "age identity file or SSH private key for bundles made with --recipient")
+	importCmd.Flags().StringVar(&bundleStrategy, "strategy", "merge", "Conflict handling: merge, skip or overwrite")
+}
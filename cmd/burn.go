@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/burn"
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	burnWait  time.Duration
+	burnForce bool
+)
+
+var burnCmd = &cobra.Command{
+	Use:   "burn",
+	Short: "Destroy every VM and erase all local entropy state",
+	Long: `Emergency teardown. Destroys every VM the orchestrator lists for your linked
+identities and waits for it to confirm, then overwrites and deletes the registry
+(with its WAL, encrypted copy and backups), logs, generated SSH keys, cached
+manifests, lock files, the managed ssh_config and every entropy-systems keyring
+entry. Only config.json and keys.json, your settings, are kept.
+
+If any VM cannot be confirmed destroyed nothing local is erased, since the
+registry holds the names needed to retry. --force wipes regardless.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var count int64
+		db.DB.Model(&db.LocalVM{}).Count(&count)
+
+		// The prompt stays off stdout so --json output remains parseable
+		var out io.Writer = os.Stdout
+		if outputJSON {
+			out = os.Stderr
+		}
+		fmt.Fprintf(out, icon("🔥")+"BURN: destroys every remote VM (%d tracked locally) and erases the entropy state in %s and all keyring entries.\n", count, config.Dir())
+		fmt.Fprintf(out, "   This cannot be undone. Type burn to confirm: ")
+
+		var confirm string
+		fmt.Scanln(&confirm)
+		if confirm != "burn" {
+			fmt.Fprintln(out, "Burn cancelled.")
+			return
+		}
+
+		rep := &burn.Report{}
+		client, err := api.NewClient(payMethod)
+		if err != nil {
+			if !burnForce {
+				fmt.Printf(mark().Fail+"Cannot tear down the fleet: %v\n", err)
+				fmt.Println("   Nothing was erased. Re-run with --force to wipe local state anyway.")
+				return
+			}
+		} else {
+			icons := map[burn.Step]string{burn.Destroying: "🔥", burn.Confirming: "⏳"}
+			notify := func(step burn.Step, s string) {
+				if !outputJSON {
+					fmt.Println(icon(icons[step]) + s)
+				}
+			}
+			rep, err = burn.Teardown(cmd.Context(), client, burnWait, notify)
+			if err != nil && !burnForce {
+				printBurnReport(rep, false)
+				if !outputJSON {
+					fmt.Printf("\n"+mark().Fail+"%v. Nothing local was erased; fix the failures above and retry, or use --force.\n", err)
+				}
+				return
+			}
+		}
+
+		burn.Wipe(rep)
+		printBurnReport(rep, true)
+	},
+}
+
+func printBurnReport(rep *burn.Report, wiped bool) {
+	if outputJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{"wiped": wiped, "report": rep}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(rep.Destroyed) > 0 {
+		fmt.Printf("\nDESTROYED: %s\n", strings.Join(rep.Destroyed, ", "))
+		if rep.Confirmed {
+			fmt.Println(mark().OK + "Orchestrator confirmed every teardown.")
+		}
+	}
+	for _, id := range rep.Orphans {
+		fmt.Printf(mark().Warn+"Remote VM %d is not in the local registry and could not be destroyed.\n", id)
+	}
+	if wiped {
+		fmt.Printf("\n"+icon("🧹")+"Erased %d item(s).\n", len(rep.Removed))
+	}
+	if len(rep.Failed) > 0 {
+		fmt.Println("\n" + mark().Fail + "COULD NOT REMOVE:")
+		for _, f := range rep.Failed {
+			fmt.Printf("   %s: %s\n", f.Item, f.Error)
+		}
+	} else if wiped {
+		fmt.Println(mark().OK + "Burn complete.")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(burnCmd)
+	burnCmd.Flags().DurationVar(&burnWait, "wait", 2*time.Minute, "How long to wait for the orchestrator to confirm teardown")
+	burnCmd.Flags().BoolVar(&burnForce, "force", false, "Wipe local state even if some VMs could not be confirmed destroyed")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost [alias]",
+	Short: "Break down what a VM has cost across provision and renewals",
+	Long: `Lists every payment recorded for a VM with its lifetime total, renewal count,
+leased hours and effective hourly rate. USDC and XMR are totalled separately.
+Payments are recorded from the settlement of each provision and renewal, so
+nodes created before cost tracking show no history.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alias := args[0]
+
+		var vm db.LocalVM
+		if err := db.DB.Where("alias = ? OR server_name = ?", alias, alias).First(&vm).Error; err != nil {
+			fmt.Printf(mark().Fail+"VM [%s] not found in local registry.\n", alias)
+			return
+		}
+
+		cost, err := fleet.CostOf(vm.ProviderID)
+		if err != nil {
+			fmt.Printf(mark().Fail+"Could not read payments: %v\n", err)
+			return
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"alias":        vm.Alias,
+				"provider_id":  vm.ProviderID,
+				"created_at":   vm.CreatedAt,
+				"expires_at":   vm.ExpiresAt,
+				"age":          fleet.Age(vm),
+				"totals":       cost.Totals,
+				"hourly_rate":  cost.HourlyRate,
+				"renewals":     cost.Renewals,
+				"leased_hours": cost.LeasedHours,
+				"payments":     cost.Payments,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		th := cliTheme()
+		headerStyle := lipgloss.NewStyle().Foreground(th.Accent).Bold(true).Padding(0, 1)
+
+		fmt.Println(headerStyle.Render(fmt.Sprintf("\n[ COST: %s ]", vm.Alias)))
+		if len(cost.Payments) == 0 {
+			fmt.Println(mark().Warn + "No payments recorded for this node.")
+			return
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(th.Muted)).
+			Headers("PAID", "KIND", "LEASE", "AMOUNT", "TX_HASH")
+		for _, p := range cost.Payments {
+			t.Row(p.PaidAt.Local().Format("2006-01-02 15:04"), p.Kind, p.Duration, fmt.Sprintf("%.4g %s", p.Value, p.Unit), p.TxHash)
+		}
+		fmt.Println(t.Render())
+
+		fmt.Printf("\nTOTAL:        %s\n", cost.String())
+		fmt.Printf("RENEWALS:     %d\n", cost.Renewals)
+		fmt.Printf("LEASED:       %.1fh\n", cost.LeasedHours)
+		fmt.Printf("HOURLY_RATE:  %s\n", cost.FormatRate())
+		fmt.Printf("AGE:          %s\n", fleet.Age(vm))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(costCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/creds"
+	"github.com/x402-Systems/entropy/internal/db"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+)
+
+var (
+	credsCopy       bool
+	credsClearAfter time.Duration
+)
+
+var credsCmd = &cobra.Command{
+	Use:   "creds [alias]",
+	Short: "Reveal the stored root password of a VM",
+	Long: `Root passwords are saved to the OS keyring when a VM is provisioned and deleted
+when it is destroyed. Once its lease is over and the orchestrator no longer lists
+it, the password is deleted by the next 'entropy ls' or TUI sync made as the
+identity that provisioned it. The password is only revealed after confirmation.
+With --copy it goes to the clipboard instead of the screen and is cleared again
+after --clear-after (30s by default).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alias := args[0]
+
+		var vm db.LocalVM
+		if err := db.DB.Where("alias = ? OR server_name = ?", alias, alias).First(&vm).Error; err != nil {
+			fmt.Printf(mark().Fail+"VM [%s] not found in local registry.\n", alias)
+			return
+		}
+
+		password, err := creds.Get(vm.ProviderID)
+		if errors.Is(err, creds.ErrNotFound) {
+			fmt.Printf(mark().Fail+"No root password stored for %s. It was provisioned before passwords were saved, or on another machine.\n", vm.Alias)
+			return
+		}
+		if err != nil {
+			fmt.Printf(mark().Fail+"Keyring error: %v\n", err)
+			return
+		}
+
+		// The prompt stays off stdout so --json output remains parseable
+		var out io.Writer = os.Stdout
+		if outputJSON {
+			out = os.Stderr
+		}
+		fmt.Fprintf(out, icon("🔑")+"Reveal the root password for %s? [y/N]: ", vm.Alias)
+		var confirm string
+		fmt.Scanln(&confirm)
+		if confirm != "y" && confirm != "Y" {
+			fmt.Fprintln(out, "Cancelled.")
+			return
+		}
+
+		if !credsCopy {
+			if outputJSON {
+				data, _ := json.MarshalIndent(map[string]interface{}{
+					"alias": vm.Alias, "provider_id": vm.ProviderID, "password": password,
+				}, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			fmt.Printf("PASSWORD: %s\n", password)
+			return
+		}
+
+		if err := clipboard.WriteAll(password); err != nil {
+			fmt.Printf(mark().Fail+"Clipboard unavailable: %v\n", err)
+			return
+		}
+		if credsClearAfter <= 0 {
+			fmt.Fprintf(out, icon("📋")+"Root password for %s copied to the clipboard.\n", vm.Alias)
+			return
+		}
+		fmt.Fprintf(out, icon("📋")+"Root password for %s copied. Clearing the clipboard in %s (Ctrl+C clears now)...\n", vm.Alias, credsClearAfter)
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		select {
+		case <-time.After(credsClearAfter):
+		case <-ctx.Done():
+		}
+		clearClipboard(password)
+		fmt.Fprintln(out, icon("🧹")+"Clipboard cleared.")
+	},
+}
+
+// clearClipboard empties the clipboard unless something else was copied since
+func clearClipboard(password string) {
+	if current, err := clipboard.ReadAll(); err == nil && current != password {
+		return
+	}
+	clipboard.WriteAll("")
+}
+
+func init() {
+	rootCmd.AddCommand(credsCmd)
+	credsCmd.Flags().BoolVarP(&credsCopy, "copy", "c", false, "Copy the password to the clipboard instead of printing it")
+	credsCmd.Flags().DurationVar(&credsClearAfter, "clear-after", 30*time.Second, "Clear the clipboard after this long (0 leaves it)")
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateStatus     bool
+	encryptPassphrase bool
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and maintain the local registry",
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending registry migrations",
+	Long: `Pending migrations are applied automatically on every other command, after
+a timestamped backup of entropy.db. Use --status to list each migration and
+when it was applied without applying anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !migrateStatus {
+			backup, err := db.Migrate()
+			if err != nil {
+				fmt.Printf(mark().Fail+"Migration failed: %v\n", err)
+				if backup != "" {
+					fmt.Printf("   Backup: %s\n", backup)
+				}
+				return
+			}
+			if backup != "" && !outputJSON {
+				fmt.Printf(icon("💾")+"Backup written to %s\n", backup)
+			}
+		}
+
+		states, err := db.MigrationStatus()
+		if err != nil {
+			fmt.Printf(mark().Fail+"Failed to read schema_version: %v\n", err)
+			return
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(states, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		path := db.Path()
+		if db.Encrypted() {
+			path = db.EncryptedPath()
+		}
+		fmt.Printf("REGISTRY: %s (%s)\n\n", path, db.Method())
+		pending := 0
+		for _, s := range states {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC1123)
+			} else {
+				pending++
+			}
+			fmt.Printf("%4d  %-24s %s\n", s.Version, s.Name, applied)
+		}
+		if pending == 0 {
+			fmt.Println("\n" + mark().OK + "Registry schema is up to date.")
+		} else {
+			fmt.Printf("\n"+mark().Warn+"%d migration(s) pending. Run 'entropy db migrate'.\n", pending)
+		}
+	},
+}
+
+// ManagesSchema reports whether args run `entropy db migrate`, which must
+// see pending migrations instead of having them applied at startup
+func ManagesSchema(args []string) bool {
+	c, _, err := rootCmd.Find(args)
+	return err == nil && c == dbMigrateCmd
+}
+
+var dbEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the registry at rest",
+	Long: `Seals entropy.db into entropy.db.enc and removes the plaintext file and its
+backups. The key is generated and stored in the system keyring, or with
+--passphrase derived from a passphrase that is asked for on every start
+(or read from ENTROPY_DB_PASSPHRASE).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if db.Encrypted() {
+			fmt.Printf(mark().Warn+"Registry is already encrypted (%s).\n", db.Method())
+			return
+		}
+
+		var pass []byte
+		if encryptPassphrase {
+			var err error
+			if env := os.Getenv("ENTROPY_DB_PASSPHRASE"); env != "" {
+				pass = []byte(env)
+			} else if pass, err = readPassphrase("Registry passphrase: ", true); err != nil {
+				fmt.Printf(mark().Fail+"%v\n", err)
+				return
+			}
+			if len(pass) == 0 {
+				fmt.Println(mark().Fail + "An empty passphrase would leave the registry unprotected.")
+				return
+			}
+		}
+
+		removed, err := db.EncryptInPlace(pass)
+		// Once the sealed file exists only the plaintext cleanup can have failed
+		if err != nil && !db.Encrypted() {
+			fmt.Printf(mark().Fail+"Encryption failed, registry unchanged: %v\n", err)
+			return
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"status": "success", "path": db.EncryptedPath(), "method": db.Method(), "removed": removed,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf(icon("🔒")+"Registry encrypted with a %s key: %s\n", db.Method(), db.EncryptedPath())
+		for _, r := range removed {
+			fmt.Printf("   shredded %s\n", r)
+		}
+		if len(removed) > 0 {
+			fmt.Println("   Plaintext was overwritten before deletion; on SSDs and copy-on-write filesystems that is best effort.")
+		}
+		if err != nil {
+			fmt.Printf(mark().Warn+"Some plaintext files could not be removed: %v\n", err)
+		}
+	},
+}
+
+var dbDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Turn registry encryption off",
+	Long:  `Writes the registry back to a plaintext entropy.db and deletes entropy.db.enc and its key.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !db.Encrypted() {
+			fmt.Println(mark().Warn + "Registry is not encrypted.")
+			return
+		}
+		if err := db.DecryptInPlace(); err != nil {
+			fmt.Printf(mark().Fail+"Decryption failed: %v\n", err)
+			return
+		}
+		if outputJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{"status": "success", "path": db.Path()}, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Printf(icon("🔓")+"Registry decrypted to %s\n", db.Path())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbEncryptCmd)
+	dbCmd.AddCommand(dbDecryptCmd)
+	dbMigrateCmd.Flags().BoolVar(&migrateStatus, "status", false, "List migrations without applying any")
+	dbEncryptCmd.Flags().BoolVar(&encryptPassphrase, "passphrase", false, "Derive the key from a passphrase instead of the keyring")
+
+	db.PassphraseFunc = func() ([]byte, error) {
+		return readPassphrase("Registry passphrase: ", false)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import "testing"
+
+func TestManagesSchema(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"db", "migrate"}, true},
+		{[]string{"db", "migrate", "--status"}, true},
+		{[]string{"--json", "db", "migrate", "--status"}, true},
+		{[]string{"db"}, false},
+		{[]string{"db", "encrypt"}, false},
+		{[]string{"ls"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := ManagesSchema(tt.args); got != tt.want {
+			t.Errorf("ManagesSchema(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
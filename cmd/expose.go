@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	publicPort int
+	exposeBind string
+)
+
+var exposeCmd = &cobra.Command{
+	Use:   "expose [alias] [local-port]",
+	Short: "Publish a local port on a node's public IP (reverse tunnel)",
+	Long: `Opens an SSH remote forward from the node's public interface to a port on this
+machine. GatewayPorts is enabled on the node during setup and the tunnel reconnects
+automatically if the connection drops. Press Ctrl+C to stop.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		vm, ok := findReachableVM(args[0])
+		if !ok {
+			return
+		}
+
+		localPort, err := strconv.Atoi(args[1])
+		if err != nil || localPort < 1 || localPort > 65535 {
+			fmt.Printf("❌ Invalid local port: %s\n", args[1])
+			return
+		}
+		if publicPort == 0 {
+			publicPort = localPort
+		}
+
+		localAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))
+		remoteAddr := net.JoinHostPort(exposeBind, strconv.Itoa(publicPort))
+
+		// Load the key once so reconnects don't prompt for the passphrase again
+		signer, err := sshmgr.LoadSigner(vm.SSHKeyPath, keyPassphrasePrompt(vm.SSHKeyPath))
+		if err != nil {
+			fmt.Printf("❌ Failed to load SSH key: %v\n", err)
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		fmt.Printf("🔧 Enabling GatewayPorts on %s...\n", vm.Alias)
+		setup, err := sshmgr.DialWithSigner(vm.IP, signer)
+		if err != nil {
+			fmt.Printf("❌ Cannot connect to %s: %v\n", vm.Alias, err)
+			return
+		}
+		if err := sshmgr.EnableGatewayPorts(setup); err != nil {
+			setup.Close()
+			fmt.Printf("❌ Failed to configure sshd on the node: %v\n", err)
+			return
+		}
+		setup.Close()
+
+		url := fmt.Sprintf("http://%s", vm.IP)
+		if publicPort != 80 {
+			url = fmt.Sprintf("http://%s:%d", vm.IP, publicPort)
+		}
+		fmt.Printf("🌍 Forwarding %s -> %s\n", url, localAddr)
+		fmt.Println("   Press Ctrl+C to stop.")
+
+		backoff := time.Second
+		for {
+			client, err := sshmgr.DialWithSigner(vm.IP, signer)
+			if err == nil {
+				backoff = time.Second
+				err = sshmgr.RemoteForward(ctx, client, remoteAddr, localAddr)
+				client.Close()
+			}
+
+			if ctx.Err() != nil {
+				fmt.Println("\n🛑 Tunnel closed.")
+				return
+			}
+
+			fmt.Printf("⚠️  Tunnel dropped (%v). Reconnecting in %s...\n", err, backoff)
+			select {
+			case <-ctx.Done():
+				fmt.Println("\n🛑 Tunnel closed.")
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exposeCmd)
+	exposeCmd.Flags().IntVar(&publicPort, "public-port", 0, "Port to open on the node (defaults to the local port)")
+	exposeCmd.Flags().StringVar(&exposeBind, "bind", "0.0.0.0", "Node interface to listen on")
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var rotateAll bool
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage SSH access keys on running nodes",
+}
+
+var keysRotateCmd = &cobra.Command{
+	Use:   "rotate [alias]",
+	Short: "Replace a node's SSH key without destroying the lease",
+	Long: `Generates a new per-node keypair, installs it over the current session, verifies
+login with it and only then revokes the old key. Any failure rolls the node back.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if rotateAll && len(args) > 0 {
+			return fmt.Errorf("pass either an alias or --all, not both")
+		}
+		if !rotateAll && len(args) != 1 {
+			return fmt.Errorf("requires an alias or --all")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var targets []db.LocalVM
+		if rotateAll {
+			live, err := db.LiveVMs()
+			if err != nil {
+				fmt.Printf("❌ Failed to read local registry: %v\n", err)
+				return
+			}
+			targets = live
+		} else {
+			var vm db.LocalVM
+			if err := db.DB.Where("alias = ? OR server_name = ?", args[0], args[0]).First(&vm).Error; err != nil {
+				fmt.Printf("❌ VM [%s] not found in local registry.\n", args[0])
+				return
+			}
+			targets = append(targets, vm)
+		}
+
+		if len(targets) == 0 {
+			fmt.Println("No live nodes to rotate.")
+			return
+		}
+
+		var passphrase []byte
+		if encryptKey {
+			var err error
+			passphrase, err = readPassphrase("New key passphrase: ", true)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+		}
+
+		results := []map[string]string{}
+		failed := 0
+		for _, vm := range targets {
+			if !outputJSON {
+				fmt.Printf("🔑 Rotating key for %s (%s)...\n", vm.Alias, vm.IP)
+			}
+
+			newKey, err := rotateNodeKey(vm, passphrase)
+			res := map[string]string{"alias": vm.Alias, "status": "rotated", "key": newKey}
+			if err != nil {
+				failed++
+				res = map[string]string{"alias": vm.Alias, "status": "failed", "error": err.Error()}
+				if !outputJSON {
+					fmt.Printf("❌ %s: %v\n", vm.Alias, err)
+				}
+			} else if !outputJSON {
+				fmt.Printf("✅ %s now uses %s\n", vm.Alias, newKey)
+			}
+			results = append(results, res)
+		}
+		sshmgr.SyncConfig()
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(data))
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// rotateNodeKey swaps vm's authorized key for a freshly generated one. The old
+// key stays authorized until the new one has been proven to work.
+func rotateNodeKey(vm db.LocalVM, passphrase []byte) (string, error) {
+	if vm.IP == "" || vm.IP == "IP-Allocating" {
+		return "", fmt.Errorf("IP is still being allocated")
+	}
+
+	oldPub, err := sshmgr.PublicKey(vm.SSHKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot read current public key: %w", err)
+	}
+
+	oldClient, err := sshmgr.Connect(vm.IP, vm.SSHKeyPath, keyPassphrasePrompt(vm.SSHKeyPath))
+	if err != nil {
+		return "", fmt.Errorf("cannot connect with current key: %w", err)
+	}
+	defer oldClient.Close()
+
+	newKey, err := sshmgr.GenerateRotationKey(vm.ProviderID, passphrase)
+	if err != nil {
+		return "", err
+	}
+	newPubLine, err := os.ReadFile(newKey)
+	if err != nil {
+		sshmgr.RemoveNodeKey(newKey)
+		return "", err
+	}
+	newPub, err := sshmgr.PublicKey(newKey)
+	if err != nil {
+		sshmgr.RemoveNodeKey(newKey)
+		return "", err
+	}
+
+	rollback := func(cause error) (string, error) {
+		if _, err := sshmgr.RemoveAuthorizedKey(oldClient, newPub); err != nil {
+			cause = fmt.Errorf("%w (rollback failed, new key may still be authorized: %v)", cause, err)
+		}
+		sshmgr.RemoveNodeKey(newKey)
+		return "", cause
+	}
+
+	if err := sshmgr.AddAuthorizedKey(oldClient, string(newPubLine)); err != nil {
+		sshmgr.RemoveNodeKey(newKey)
+		return "", fmt.Errorf("failed to install new key: %w", err)
+	}
+
+	signer, err := sshmgr.LoadSigner(newKey, func() ([]byte, error) { return passphrase, nil })
+	if err != nil {
+		return rollback(err)
+	}
+	newClient, err := sshmgr.DialWithSigner(vm.IP, signer)
+	if err != nil {
+		return rollback(fmt.Errorf("login with new key failed: %w", err))
+	}
+	defer newClient.Close()
+
+	if _, err := sshmgr.Run(newClient, "true", nil); err != nil {
+		return rollback(fmt.Errorf("login with new key failed: %w", err))
+	}
+
+	if _, err := sshmgr.RemoveAuthorizedKey(newClient, oldPub); err != nil {
+		return rollback(fmt.Errorf("failed to revoke old key: %w", err))
+	}
+
+	if err := db.DB.Model(&vm).Update("ssh_key_path", newKey).Error; err != nil {
+		// The old key is gone remotely, so restore it before undoing the new one
+		if restoreErr := sshmgr.AddAuthorizedKey(newClient, string(ssh.MarshalAuthorizedKey(oldPub))); restoreErr != nil {
+			return "", fmt.Errorf("local DB update failed (%v) and old key could not be restored: %v; new key kept at %s", err, restoreErr, newKey)
+		}
+		return rollback(fmt.Errorf("local DB update failed: %w", err))
+	}
+
+	sshmgr.RemoveFromAgent(vm.SSHKeyPath)
+	sshmgr.RemoveNodeKey(vm.SSHKeyPath)
+	return newKey, nil
+}
+
+func init() {
+	rootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(keysRotateCmd)
+
+	keysRotateCmd.Flags().BoolVar(&rotateAll, "all", false, "Rotate keys on every live node")
+	keysRotateCmd.Flags().BoolVar(&encryptKey, "encrypt-key", false, "Protect the new private key with a passphrase")
+}
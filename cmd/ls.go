@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
 	"io"
+	"os"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
@@ -21,13 +24,20 @@ var lsCmd = &cobra.Command{
 
 		client, err := api.NewClient(payMethod)
 		if err != nil {
-			fmt.Printf("⚠️  Offline Mode: %v\n", err)
+			if outputJSON {
+				printLsJSON(locals)
+				return
+			}
+			fmt.Printf(mark().Warn+"Offline Mode: %v\n", err)
 			renderTable(locals, nil)
+			if showAccess {
+				renderAccess(locals)
+			}
 			return
 		}
 
 		if !outputJSON {
-			fmt.Println("📡 Syncing with X402 Gateway...")
+			fmt.Println(icon("📡") + "Syncing with X402 Gateway...")
 		}
 		resp, err := client.DoRequest(cmd.Context(), "GET", "/list", nil, nil)
 
@@ -42,34 +52,85 @@ var lsCmd = &cobra.Command{
 				for _, r := range listResp.VMs {
 					remotes[r.ProviderID] = r
 				}
+				if resp.StatusCode == 200 {
+					fleet.PruneCredentials(client.PayerID, remotes)
+				}
 			}
 		}
 
+		if remotes != nil {
+			defer sshmgr.SyncConfig()
+		}
+
 		if outputJSON {
-			data, _ := json.MarshalIndent(locals, "", "  ")
-			fmt.Println(string(data))
+			printLsJSON(locals)
 			return
 		}
 
 		renderTable(locals, remotes)
+		if showAccess {
+			renderAccess(locals)
+		}
 	},
 }
 
+var showAccess bool
+
+// lsEntry is a registry row plus what it has cost so far
+type lsEntry struct {
+	db.LocalVM
+	Cost *fleet.Cost `json:"cost"`
+	Age  string      `json:"age"`
+}
+
+// summaryCost drops the individual payments; entropy cost lists those
+func summaryCost(c *fleet.Cost) *fleet.Cost {
+	if c == nil {
+		return &fleet.Cost{Totals: map[string]float64{}, HourlyRate: map[string]float64{}}
+	}
+	sum := *c
+	sum.Payments = nil
+	return &sum
+}
+
+func printLsJSON(locals []db.LocalVM) {
+	// stderr keeps stdout valid JSON
+	costs, err := fleet.Costs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, mark().Warn+"Cost history unavailable: %v\n", err)
+	}
+	entries := make([]lsEntry, len(locals))
+	for i, l := range locals {
+		entries[i] = lsEntry{LocalVM: l, Cost: summaryCost(costs[l.ProviderID]), Age: fleet.Age(l)}
+	}
+	var out interface{} = entries
+	if showAccess {
+		var grants []db.AccessGrant
+		db.DB.Order("provider_id, grantee").Find(&grants)
+		out = map[string]interface{}{"vms": entries, "grants": grants}
+	}
+	data, _ := json.MarshalIndent(out, "", "  ")
+	fmt.Println(string(data))
+}
+
 func renderTable(locals []db.LocalVM, remotes map[int64]api.RemoteVM) {
-	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true).Padding(0, 1)
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
+	th := cliTheme()
+	headerStyle := lipgloss.NewStyle().Foreground(th.Accent).Bold(true).Padding(0, 1)
+	borderStyle := lipgloss.NewStyle().Foreground(th.Muted)
 
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderStyle(borderStyle).
-		Headers("ALIAS", "IP_ADDRESS", "TIER", "REGION", "STATUS", "TTL")
+		Headers("ALIAS", "IP_ADDRESS", "TIER", "REGION", "STATUS", "TTL", "COST", "RENEWALS", "AGE")
+
+	costs, costErr := fleet.Costs()
 
 	for _, l := range locals {
-		status := lipgloss.NewStyle().Foreground(lipgloss.Color("#444444")).Render("EXPIRED")
+		status := lipgloss.NewStyle().Foreground(th.Muted).Render("EXPIRED")
 		ttl := "0s"
 
 		if r, ok := remotes[l.ProviderID]; ok {
-			status = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Render("ALIVE")
+			status = lipgloss.NewStyle().Foreground(th.Good).Render("ALIVE")
 			ttl = r.TimeRemaining
 
 			// Update local IP if it was pending
@@ -79,6 +140,7 @@ func renderTable(locals []db.LocalVM, remotes map[int64]api.RemoteVM) {
 			}
 		}
 
+		cost := summaryCost(costs[l.ProviderID])
 		t.Row(
 			l.Alias,
 			l.IP,
@@ -86,14 +148,60 @@ func renderTable(locals []db.LocalVM, remotes map[int64]api.RemoteVM) {
 			l.Region,
 			status,
 			ttl,
+			cost.String(),
+			fmt.Sprint(cost.Renewals),
+			fleet.Age(l),
 		)
 	}
 
 	fmt.Println(headerStyle.Render("\n[ X402_FLEET_MANIFEST ]"))
 	fmt.Println(t.Render())
 	fmt.Printf("\nTotal tracked nodes: %d\n", len(locals))
+	if costErr != nil {
+		fmt.Printf(mark().Warn+"Cost history unavailable: %v\n", costErr)
+	}
+}
+
+func renderAccess(locals []db.LocalVM) {
+	th := cliTheme()
+	headerStyle := lipgloss.NewStyle().Foreground(th.Accent).Bold(true).Padding(0, 1)
+	borderStyle := lipgloss.NewStyle().Foreground(th.Muted)
+
+	aliases := make(map[int64]string)
+	for _, l := range locals {
+		aliases[l.ProviderID] = l.Alias
+	}
+
+	var grants []db.AccessGrant
+	db.DB.Order("provider_id, grantee").Find(&grants)
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(borderStyle).
+		Headers("NODE", "GRANTEE", "FINGERPRINT", "GRANTED", "EXPIRES")
+
+	for _, g := range grants {
+		node, ok := aliases[g.ProviderID]
+		if !ok {
+			continue
+		}
+
+		expires := "never"
+		if g.ExpiresAt != nil {
+			expires = g.ExpiresAt.Local().Format("2006-01-02 15:04")
+			if g.Expired() {
+				expires = lipgloss.NewStyle().Foreground(th.Muted).Render("EXPIRED")
+			}
+		}
+
+		t.Row(node, g.Grantee, g.Fingerprint, g.CreatedAt.Local().Format("2006-01-02 15:04"), expires)
+	}
+
+	fmt.Println(headerStyle.Render("\n[ SHARED_ACCESS ]"))
+	fmt.Println(t.Render())
 }
 
 func init() {
 	rootCmd.AddCommand(lsCmd)
+	lsCmd.Flags().BoolVar(&showAccess, "access", false, "Also list teammate SSH grants per node")
 }
@@ -18,12 +18,12 @@ var optionsCmd = &cobra.Command{
 	Short: "List available hardware tiers, regions, and distros",
 	Run: func(cmd *cobra.Command, args []string) {
 		if !outputJSON {
-			fmt.Printf("📡 Querying available resources from %s...\n", config.BaseURL)
+			fmt.Printf(icon("📡")+"Querying available resources from %s...\n", config.BaseURL)
 		}
 
 		resp, err := http.Get(config.BaseURL + "/options")
 		if err != nil {
-			fmt.Printf("❌ Orchestrator unreachable: %v\n", err)
+			fmt.Printf(mark().Fail+"Orchestrator unreachable: %v\n", err)
 			return
 		}
 		defer resp.Body.Close()
@@ -31,7 +31,7 @@ var optionsCmd = &cobra.Command{
 		body, _ := io.ReadAll(resp.Body)
 		var options map[string]interface{}
 		if err := json.Unmarshal(body, &options); err != nil {
-			fmt.Printf("❌ Failed to parse manifest: %v\n", err)
+			fmt.Printf(mark().Fail+"Failed to parse manifest: %v\n", err)
 			return
 		}
 
@@ -45,14 +45,13 @@ var optionsCmd = &cobra.Command{
 }
 
 func renderOptions(data map[string]interface{}) {
-	red := lipgloss.Color("#FF0000")
-	grey := lipgloss.Color("#444444")
-	headerStyle := lipgloss.NewStyle().Foreground(red).Bold(true).MarginTop(1)
+	th := cliTheme()
+	headerStyle := lipgloss.NewStyle().Foreground(th.Accent).Bold(true).MarginTop(1)
 
 	fmt.Println(headerStyle.Render("[ AVAILABLE_HARDWARE_TIERS ]"))
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(grey)).
+		BorderStyle(lipgloss.NewStyle().Foreground(th.Muted)).
 		Headers("TIER", "CPU", "RAM", "DISK", "REGIONS (EST. HOURLY)")
 
 	if tiers, ok := data["tiers"].(map[string]interface{}); ok {
@@ -89,7 +88,7 @@ func renderOptions(data map[string]interface{}) {
 	}
 
 	if note, ok := data["note"].(string); ok {
-		fmt.Printf("\n%s\n", lipgloss.NewStyle().Foreground(grey).Italic(true).Render("NOTE: "+note))
+		fmt.Printf("\n%s\n", lipgloss.NewStyle().Foreground(th.Muted).Italic(true).Render("NOTE: "+note))
 	}
 }
 
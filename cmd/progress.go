@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"golang.org/x/term"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// stageSpinner prints each x402 stage as it is reached, with a spinner for the
+// one in flight. Without a terminal only the stage list is printed.
+type stageSpinner struct {
+	mu      sync.Mutex
+	waiting string
+	started time.Time
+	ok      string
+	tty     bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// trackStages wires a spinner into client; call Stop before printing results
+func trackStages(client *api.Client, waiting string) *stageSpinner {
+	s := &stageSpinner{
+		waiting: waiting,
+		started: time.Now(),
+		ok:      mark().OK,
+		tty:     term.IsTerminal(int(os.Stdout.Fd())),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	client.OnProgress = s.report
+
+	if !s.tty {
+		close(s.done)
+		return s
+	}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-s.stop:
+				s.mu.Lock()
+				fmt.Print("\r\033[K")
+				s.mu.Unlock()
+				return
+			case <-ticker.C:
+			}
+			s.mu.Lock()
+			fmt.Printf("\r\033[K%s %s (%s)", spinnerFrames[i%len(spinnerFrames)], s.waiting, time.Since(s.started).Round(time.Second))
+			s.mu.Unlock()
+		}
+	}()
+	return s
+}
+
+func (s *stageSpinner) report(p api.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := fmt.Sprintf("   %s%s", s.ok, p.Stage)
+	if p.Detail != "" {
+		line += " (" + p.Detail + ")"
+	}
+	if s.tty {
+		fmt.Print("\r\033[K")
+	}
+	fmt.Println(line)
+
+	s.waiting = p.Waiting()
+}
+
+// Stop clears the spinner line. It is safe on a nil spinner.
+func (s *stageSpinner) Stop() {
+	if s == nil {
+		return
+	}
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
@@ -2,11 +2,11 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/db"
-	"io"
-	"net/url"
+	"github.com/x402-Systems/entropy/internal/fleet"
 
 	"github.com/spf13/cobra"
 )
@@ -30,29 +30,21 @@ var renewCmd = &cobra.Command{
 			return
 		}
 
-		params := url.Values{}
-		params.Add("vm_name", vm.ServerName)
-		params.Add("duration", duration)
-
+		var stages *stageSpinner
 		if !outputJSON {
 			fmt.Printf("⏳ Renewing %s for another %s...\n", alias, duration)
+			stages = trackStages(client, "contacting the orchestrator")
 		}
 
-		headers := map[string]string{"X-VM-NAME": vm.ServerName, "X-VM-DURATION": duration}
-		resp, err := client.DoRequest(cmd.Context(), "POST", "/renew?"+params.Encode(), nil, headers)
-		if err != nil || resp.StatusCode != 200 {
-			fmt.Println("❌ Renewal failed. Check balance or if VM is already reaped.")
+		serverRes, err := fleet.Renew(cmd.Context(), client, vm, duration)
+		stages.Stop()
+		if err != nil && !errors.Is(err, fleet.ErrNotRecorded) {
+			fmt.Printf("❌ Renewal failed. Check balance or if VM is already reaped. (%v)\n", err)
 			return
 		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var serverRes struct {
-			Status    string `json:"status"`
-			NewExpiry string `json:"new_expiry"`
-			Message   string `json:"message"`
+		if err != nil && !outputJSON {
+			fmt.Printf("⚠️  %v\n", err)
 		}
-		json.Unmarshal(body, &serverRes)
 
 		if outputJSON {
 			res := map[string]interface{}{
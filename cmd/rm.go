@@ -1,10 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/db"
-	"net/url"
+	"github.com/x402-Systems/entropy/internal/fleet"
 
 	"github.com/spf13/cobra"
 )
@@ -28,23 +29,17 @@ var rmCmd = &cobra.Command{
 			return
 		}
 
-		params := url.Values{}
-		params.Add("vm_name", vm.ServerName)
-
 		if !outputJSON {
 			fmt.Printf("🗑️ Sending teardown signal for %s...\n", vm.Alias)
 		}
 
-		headers := map[string]string{"X-VM-NAME": vm.ServerName}
-		resp, err := client.DoRequest(cmd.Context(), "DELETE", "/provision?"+params.Encode(), nil, headers)
-		if err != nil || resp.StatusCode != 200 {
-			fmt.Println("❌ Teardown failed. The server may have already reaped this instance.")
-			return
-		}
-
-		if err := db.DB.Delete(&vm).Error; err != nil {
+		if err := fleet.Destroy(cmd.Context(), client, vm); err != nil {
+			if !errors.Is(err, fleet.ErrLocalCleanup) {
+				fmt.Printf("❌ Teardown failed: %v\n", err)
+				return
+			}
 			if !outputJSON {
-				fmt.Printf("⚠️  VM destroyed on server but local DB update failed: %v\n", err)
+				fmt.Printf("⚠️  %v\n", err)
 			}
 		}
 
@@ -2,16 +2,20 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"syscall"
 
 	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+	"github.com/x402-Systems/entropy/internal/theme"
 	"github.com/x402-Systems/entropy/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
 )
 
 var (
@@ -20,7 +24,10 @@ var (
 	payMethod      string
 )
 
-var outputJSON bool
+var (
+	outputJSON   bool
+	syncInterval string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "entropy",
@@ -47,6 +54,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Output response in raw JSON format")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().StringVarP(&payMethod, "pay", "p", "usdc", "Payment method (usdc or xmr)")
+	rootCmd.Flags().StringVar(&syncInterval, "sync-interval", "", "TUI auto-sync interval (e.g. 30s, 2m) or 'manual'; overrides config.json")
 }
 
 func launchTUI() {
@@ -59,25 +67,64 @@ func launchTUI() {
 		}
 	}
 
-	f, err := tea.LogToFile("entropy.log", "debug")
+	settings := config.LoadSettings()
+	if syncInterval == "" {
+		syncInterval = settings.SyncInterval
+	}
+
+	keys, err := config.LoadKeymap()
 	if err != nil {
+		fmt.Printf("⚠️  Ignoring key overrides: %v\n", err)
+	}
+
+	m := ui.InitialModel(walletAddr, ui.Config{
+		SyncEvery: config.ParseSyncInterval(syncInterval),
+		Theme:     theme.Load(settings.Theme),
+		Keys:      keys,
+
+		EVMRPC:       settings.EVMRPC,
+		USDCContract: settings.USDCContract,
+	})
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithReportFocus())
+
+	if _, err := p.Run(); err != nil {
+		slog.Error("tui exited", "err", err)
 		fmt.Println("fatal:", err)
 		os.Exit(1)
 	}
-	defer f.Close()
+}
 
-	m := ui.InitialModel(walletAddr)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+// cliTheme is the configured theme for table output; NO_COLOR is honoured
+func cliTheme() theme.Theme {
+	return theme.Load(config.LoadSettings().Theme)
+}
 
-	finalModel, err := p.Run()
-	if err != nil {
-		log.Fatal(err)
+// readPassphrase prompts on the terminal without echo. With confirm set the
+// passphrase must be typed twice.
+func readPassphrase(prompt string, confirm bool) ([]byte, error) {
+	fmt.Print(prompt)
+	first, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil || !confirm {
+		return first, err
 	}
 
-	if m, ok := finalModel.(ui.Model); ok && m.SSHToRun != "" {
-		fmt.Printf("🔌 Dropping into SSH for: %s\n", m.SSHToRun)
+	fmt.Print("Confirm passphrase: ")
+	second, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	if string(first) != string(second) {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return first, nil
+}
 
-		sshCmd.Run(sshCmd, []string{m.SSHToRun})
+// keyPassphrasePrompt is the sshmgr.PassphraseFunc used by interactive commands
+func keyPassphrasePrompt(keyPath string) sshmgr.PassphraseFunc {
+	return func() ([]byte, error) {
+		return readPassphrase(fmt.Sprintf("Passphrase for %s: ", sshmgr.PrivateKeyPath(keyPath)), false)
 	}
 }
 
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"gorm.io/gorm"
+)
+
+var (
+	sharePubKey  string
+	shareName    string
+	shareExpires string
+	unshareAll   bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share [alias]",
+	Short: "Grant a teammate SSH access to a node",
+	Long: `Installs one or more public keys into the node's authorized_keys and records the grant locally.
+--pubkey accepts a file path or authorized_keys text (e.g. the output of github.com/<user>.keys).
+With --expires the node itself refuses the key once the expiry passes.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vm, ok := findReachableVM(args[0])
+		if !ok {
+			return
+		}
+
+		if sharePubKey == "" {
+			fmt.Println("❌ --pubkey is required")
+			return
+		}
+		if shareName != "" && !validGrantee(shareName) {
+			fmt.Println("❌ --name may only contain letters, digits and . _ @ -")
+			return
+		}
+
+		keys, err := parseSharedKeys(sharePubKey)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		var expiresAt *time.Time
+		if shareExpires != "" {
+			d, err := time.ParseDuration(shareExpires)
+			if err != nil {
+				fmt.Printf("❌ Invalid --expires duration: %v\n", err)
+				return
+			}
+			t := time.Now().Add(d).UTC()
+			expiresAt = &t
+		}
+
+		if ownPub, err := sshmgr.PublicKey(vm.SSHKeyPath); err == nil {
+			for _, k := range keys {
+				if ssh.FingerprintSHA256(k.pub) == ssh.FingerprintSHA256(ownPub) {
+					fmt.Println("❌ Refusing to share the node's own management key.")
+					return
+				}
+			}
+		}
+
+		client, err := sshmgr.Connect(vm.IP, vm.SSHKeyPath, keyPassphrasePrompt(vm.SSHKeyPath))
+		if err != nil {
+			fmt.Printf("❌ Cannot connect to %s: %v\n", vm.Alias, err)
+			return
+		}
+		defer client.Close()
+
+		granted := []db.AccessGrant{}
+		for _, k := range keys {
+			grantee := shareName
+			if grantee == "" {
+				grantee = safeGrantee(k.comment)
+			}
+			if grantee == "" {
+				grantee = "unnamed"
+			}
+
+			line := authorizedLine(k, grantee, expiresAt)
+			if err := sshmgr.ReplaceAuthorizedKey(client, line); err != nil {
+				fmt.Printf("❌ Failed to install key %s: %v\n", ssh.FingerprintSHA256(k.pub), err)
+				continue
+			}
+
+			grant := db.AccessGrant{
+				ProviderID:  vm.ProviderID,
+				Grantee:     grantee,
+				Fingerprint: ssh.FingerprintSHA256(k.pub),
+				PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(k.pub))),
+				ExpiresAt:   expiresAt,
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Where("provider_id = ? AND fingerprint = ?", vm.ProviderID, grant.Fingerprint).Delete(&db.AccessGrant{}).Error; err != nil {
+					return err
+				}
+				return tx.Create(&grant).Error
+			})
+			if err != nil {
+				// Without a record unshare cannot find it, so it is not reported as granted
+				fmt.Printf("⚠️  Key %s installed for %s but failed to record grant: %v\n", grant.Fingerprint, grantee, err)
+				continue
+			}
+			granted = append(granted, grant)
+		}
+
+		if outputJSON {
+			data, _ := json.MarshalIndent(granted, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		for _, g := range granted {
+			fmt.Printf("✅ %s can now log in to %s as root (%s)\n", g.Grantee, vm.Alias, g.Fingerprint)
+		}
+		if expiresAt != nil && len(granted) > 0 {
+			fmt.Printf("   Access expires: %s\n", expiresAt.Format(time.RFC1123))
+		}
+		fmt.Printf("\nThey connect with: ssh root@%s\n", vm.IP)
+	},
+}
+
+var unshareCmd = &cobra.Command{
+	Use:   "unshare [alias]",
+	Short: "Revoke a teammate's SSH access to a node",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vm, ok := findReachableVM(args[0])
+		if !ok {
+			return
+		}
+
+		q := db.DB.Where("provider_id = ?", vm.ProviderID)
+		switch {
+		case unshareAll:
+		case shareName != "":
+			if !validGrantee(shareName) {
+				fmt.Println("❌ --name may only contain letters, digits and . _ @ -")
+				return
+			}
+			q = q.Where("grantee = ?", shareName)
+		case sharePubKey != "":
+			keys, err := parseSharedKeys(sharePubKey)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+			fps := []string{}
+			for _, k := range keys {
+				fps = append(fps, ssh.FingerprintSHA256(k.pub))
+			}
+			q = q.Where("fingerprint IN ?", fps)
+		default:
+			fmt.Println("❌ Specify --name, --pubkey or --all")
+			return
+		}
+
+		var grants []db.AccessGrant
+		q.Find(&grants)
+		if len(grants) == 0 {
+			fmt.Printf("❌ No matching grants on %s.\n", vm.Alias)
+			return
+		}
+
+		client, err := sshmgr.Connect(vm.IP, vm.SSHKeyPath, keyPassphrasePrompt(vm.SSHKeyPath))
+		if err != nil {
+			fmt.Printf("❌ Cannot connect to %s: %v\n", vm.Alias, err)
+			return
+		}
+		defer client.Close()
+
+		revoked := []string{}
+		for _, g := range grants {
+			pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(g.PublicKey))
+			if err != nil {
+				continue
+			}
+			if _, err := sshmgr.RemoveAuthorizedKey(client, pub); err != nil {
+				fmt.Printf("❌ Failed to revoke %s (%s): %v\n", g.Grantee, g.Fingerprint, err)
+				continue
+			}
+			if err := db.DB.Delete(&g).Error; err != nil {
+				fmt.Printf("⚠️  Revoked %s (%s) on the node but failed to remove the grant record: %v\n", g.Grantee, g.Fingerprint, err)
+			}
+			revoked = append(revoked, g.Grantee)
+		}
+
+		if outputJSON {
+			res := map[string]interface{}{"status": "success", "action": "unshare", "alias": vm.Alias, "revoked": revoked}
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		for _, name := range revoked {
+			fmt.Printf("✅ Revoked %s's access to %s\n", name, vm.Alias)
+		}
+	},
+}
+
+type sharedKey struct {
+	pub     ssh.PublicKey
+	comment string
+	options []string
+}
+
+// parseSharedKeys reads authorized_keys formatted text from a file path or the value itself
+func parseSharedKeys(input string) ([]sharedKey, error) {
+	data := []byte(input)
+	if content, err := os.ReadFile(input); err == nil {
+		data = content
+	}
+
+	var keys []sharedKey
+	for len(data) > 0 {
+		pub, comment, options, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			break
+		}
+		keys = append(keys, sharedKey{pub: pub, comment: comment, options: options})
+		data = rest
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no valid public keys found in --pubkey")
+	}
+	return keys, nil
+}
+
+// authorizedLine renders a grant, letting sshd enforce the expiry on the node
+func authorizedLine(k sharedKey, grantee string, expiresAt *time.Time) string {
+	opts := []string{}
+	for _, o := range k.options {
+		if !strings.HasPrefix(o, "expiry-time=") {
+			opts = append(opts, o)
+		}
+	}
+	if expiresAt != nil {
+		opts = append(opts, fmt.Sprintf(`expiry-time="%sZ"`, expiresAt.UTC().Format("200601021504")))
+	}
+
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(k.pub))) + " entropy-share:" + safeGrantee(grantee)
+	if len(opts) > 0 {
+		line = strings.Join(opts, ",") + " " + line
+	}
+	return line
+}
+
+// granteePattern is what may follow entropy-share: in authorized_keys; a
+// newline there would start a second, unrestricted key line
+var granteePattern = regexp.MustCompile(`^[A-Za-z0-9._@-]+$`)
+
+func validGrantee(name string) bool {
+	return granteePattern.MatchString(name)
+}
+
+// safeGrantee maps a key comment onto the grantee alphabet
+func safeGrantee(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && granteePattern.MatchString(string(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func findReachableVM(aliasArg string) (db.LocalVM, bool) {
+	var vm db.LocalVM
+	if err := db.DB.Where("alias = ? OR server_name = ?", aliasArg, aliasArg).First(&vm).Error; err != nil {
+		fmt.Printf("❌ VM [%s] not found in local registry.\n", aliasArg)
+		return vm, false
+	}
+	if vm.IP == "IP-Allocating" || vm.IP == "" {
+		fmt.Println("⏳ IP is still being allocated by the orchestrator. Try again in 10 seconds.")
+		return vm, false
+	}
+	return vm, true
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+	rootCmd.AddCommand(unshareCmd)
+
+	shareCmd.Flags().StringVar(&sharePubKey, "pubkey", "", "Public key file or authorized_keys text")
+	shareCmd.Flags().StringVar(&shareName, "name", "", "Who the access is for (defaults to the key comment)")
+	shareCmd.Flags().StringVar(&shareExpires, "expires", "", "Revoke automatically after this duration (e.g. 24h)")
+
+	unshareCmd.Flags().StringVar(&sharePubKey, "pubkey", "", "Revoke these keys")
+	unshareCmd.Flags().StringVar(&shareName, "name", "", "Revoke every key granted to this name")
+	unshareCmd.Flags().BoolVar(&unshareAll, "all", false, "Revoke every shared key on the node")
+}
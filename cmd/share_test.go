@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newAuthorizedKey(t *testing.T, comment string) string {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+func TestParseSharedKeys(t *testing.T) {
+	a := newAuthorizedKey(t, "alice@laptop")
+	b := newAuthorizedKey(t, "")
+	text := "# team keys\n" + a + "\n\n" + `expiry-time="209901010000Z" ` + b + "\n"
+
+	keys, err := parseSharedKeys(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("got %d keys, want 2", len(keys))
+	}
+	if keys[0].comment != "alice@laptop" {
+		t.Errorf("comment = %q, want alice@laptop", keys[0].comment)
+	}
+	if len(keys[1].options) != 1 || !strings.HasPrefix(keys[1].options[0], "expiry-time=") {
+		t.Errorf("options = %v, want the expiry-time option", keys[1].options)
+	}
+
+	path := filepath.Join(t.TempDir(), "id.pub")
+	if err := os.WriteFile(path, []byte(a+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := parseSharedKeys(path); err != nil || len(keys) != 1 {
+		t.Fatalf("from file: %d keys, err %v", len(keys), err)
+	}
+
+	if _, err := parseSharedKeys("not a key"); err == nil {
+		t.Error("expected an error for input without keys")
+	}
+}
+
+func TestAuthorizedLine(t *testing.T) {
+	keys, err := parseSharedKeys(`expiry-time="200001010000Z",no-pty ` + newAuthorizedKey(t, "bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := keys[0]
+	expires := time.Date(2030, 5, 17, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	tests := []struct {
+		name      string
+		grantee   string
+		expiresAt *time.Time
+		prefix    string
+		suffix    string
+	}{
+		{"keeps options, drops old expiry", "bob", nil, "no-pty ssh-ed25519 ", " entropy-share:bob"},
+		{"new expiry in UTC", "bob", &expires, `no-pty,expiry-time="203005170730Z" ssh-ed25519 `, " entropy-share:bob"},
+		{"space", "Jane Doe", nil, "no-pty ", " entropy-share:Jane_Doe"},
+		{"newline injection", "x\nssh-ed25519 AAAA evil", nil, "no-pty ", " entropy-share:x_ssh-ed25519_AAAA_evil"},
+	}
+	for _, tt := range tests {
+		line := authorizedLine(k, tt.grantee, tt.expiresAt)
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("%s: line break in %q", tt.name, line)
+		}
+		if !strings.HasPrefix(line, tt.prefix) || !strings.HasSuffix(line, tt.suffix) {
+			t.Errorf("%s: got %q, want prefix %q and suffix %q", tt.name, line, tt.prefix, tt.suffix)
+		}
+	}
+}
+
+func TestGrantee(t *testing.T) {
+	valid := map[string]bool{
+		"alice":             true,
+		"alice@laptop":      true,
+		"ops.team-1_b":      true,
+		"":                  false,
+		"Jane Doe":          false,
+		"x\ncommand=\"id\"": false,
+		"tab\there":         false,
+		"émile":             false,
+	}
+	for name, want := range valid {
+		if got := validGrantee(name); got != want {
+			t.Errorf("validGrantee(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	safe := map[string]string{
+		"alice@laptop": "alice@laptop",
+		"Jane Doe":     "Jane_Doe",
+		"a\r\nb":       "a__b",
+		"émile":        "_mile",
+	}
+	for in, want := range safe {
+		if got := safeGrantee(in); got != want {
+			t.Errorf("safeGrantee(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
 	"fmt"
 	"os"
 	"os/exec"
@@ -31,25 +32,19 @@ var sshCmd = &cobra.Command{
 			return
 		}
 
-		// 3. Resolve Private Key Path
-		// If the user stored the .pub path, we need the private key (usually same name without .pub)
-		privateKeyPath := vm.SSHKeyPath
-		if strings.HasSuffix(privateKeyPath, ".pub") {
-			privateKeyPath = strings.TrimSuffix(privateKeyPath, ".pub")
-		}
-
-		// 4. Build SSH Command
-		// Flags explained:
-		// -i: identity file
-		// -o StrictHostKeyChecking=no: Don't prompt to add to known_hosts (essential for ephemeral nodes)
-		// -o UserKnownHostsFile=/dev/null: Don't save the host key (prevents "Host Identification Changed" errors later)
-		sshArgs := []string{
-			"-i", privateKeyPath,
-			"-o", "StrictHostKeyChecking=no",
-			"-o", "UserKnownHostsFile=/dev/null",
-			"-o", "LogLevel=ERROR", // Hide the "Warning: Permanently added..." message
-			fmt.Sprintf("root@%s", vm.IP),
+		// 3. Build SSH Command
+		// Host keys are neither checked nor saved: ephemeral IPs get recycled between
+		// leases, so known_hosts would only produce "Host Identification Changed" errors.
+		// With --agent the key is handed to ssh-agent and ssh picks it from there
+		keyPath := vm.SSHKeyPath
+		if useAgent {
+			if err := sshmgr.AddToAgent(vm.SSHKeyPath, keyPassphrasePrompt(vm.SSHKeyPath)); err != nil {
+				fmt.Printf("❌ Failed to load key into ssh-agent: %v\n", err)
+				return
+			}
+			keyPath = ""
 		}
+		sshArgs := sshmgr.SSHArgs(vm.IP, keyPath)
 
 		isInteractive := len(args) == 1
 		if !isInteractive {
@@ -60,7 +55,7 @@ var sshCmd = &cobra.Command{
 			fmt.Printf("🚀 Connecting to %s (%s) as root...\n", vm.Alias, vm.IP)
 		}
 
-		// 5. Execute SSH
+		// 4. Execute SSH
 		c := exec.Command("ssh", sshArgs...)
 		if isInteractive {
 			// For interactive sessions, we connect the stdin to the user's terminal
@@ -83,6 +78,9 @@ var sshCmd = &cobra.Command{
 	},
 }
 
+var useAgent bool
+
 func init() {
 	rootCmd.AddCommand(sshCmd)
+	sshCmd.Flags().BoolVar(&useAgent, "agent", false, "Load the node key into ssh-agent instead of passing -i")
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/spf13/cobra"
+)
+
+var installInclude bool
+
+var sshConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Generate an OpenSSH config include for every live node",
+	Long: `Writes a managed Host block per live VM so plain ssh, rsync and IDE remote
+plugins can reach nodes by alias. The file is refreshed automatically after up, rm and syncs.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts, err := sshmgr.SyncConfig()
+		if err != nil {
+			fmt.Printf("❌ Failed to write SSH config: %v\n", err)
+			return
+		}
+
+		added := false
+		if installInclude {
+			added, err = sshmgr.InstallInclude()
+			if err != nil {
+				fmt.Printf("❌ Failed to update ~/.ssh/config: %v\n", err)
+				return
+			}
+		}
+
+		if outputJSON {
+			res := map[string]interface{}{
+				"path":              sshmgr.ConfigPath(),
+				"hosts":             hosts,
+				"include_installed": installInclude,
+				"include_added":     added,
+			}
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("✅ Wrote %d host(s) to %s\n", hosts, sshmgr.ConfigPath())
+		switch {
+		case added:
+			fmt.Println("🔗 Include line added to ~/.ssh/config.")
+		case installInclude:
+			fmt.Println("🔗 ~/.ssh/config already includes the managed file.")
+		default:
+			fmt.Println("\nRun 'entropy ssh-config --install' to include it from ~/.ssh/config.")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sshConfigCmd)
+	sshConfigCmd.Flags().BoolVar(&installInclude, "install", false, "Add the Include line to ~/.ssh/config (idempotent)")
+}
@@ -1,11 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/config"
-	"io"
-	"net/http"
 
 	"github.com/spf13/cobra"
 )
@@ -18,16 +19,11 @@ var statsCmd = &cobra.Command{
 			fmt.Printf("📡 Querying %s...\n", config.BaseURL)
 		}
 
-		resp, err := http.Get(config.BaseURL + "/stats")
+		stats, err := api.FetchStats(context.Background())
 		if err != nil {
 			fmt.Printf("❌ Orchestrator unreachable: %v\n", err)
 			return
 		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var stats map[string]interface{}
-		json.Unmarshal(body, &stats)
 
 		if outputJSON {
 			data, _ := json.MarshalIndent(stats, "", "  ")
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusMarks prefix CLI status lines. They come from the configured theme,
+// so the symbols, colours and NO_COLOR handling match the TUI.
+type statusMarks struct {
+	Fail, Warn, OK string
+	ascii          bool
+}
+
+var (
+	marksOnce sync.Once
+	marks     statusMarks
+)
+
+func mark() statusMarks {
+	marksOnce.Do(func() {
+		th := cliTheme()
+		paint := func(c lipgloss.TerminalColor, s string) string {
+			return lipgloss.NewStyle().Foreground(c).Bold(true).Render(s) + " "
+		}
+		marks = statusMarks{
+			Fail:  paint(th.Accent, th.Symbols.Fail),
+			Warn:  paint(th.Warn, th.Symbols.Warn),
+			OK:    paint(th.Good, th.Symbols.OK),
+			ascii: th.Symbols.OK != "✓",
+		}
+	})
+	return marks
+}
+
+// icon prefixes a purely decorative pictograph, which the ASCII themes leave out
+func icon(pictograph string) string {
+	if mark().ascii {
+		return ""
+	}
+	return pictograph + " "
+}
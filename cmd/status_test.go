@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func resetMarks(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	marksOnce = sync.Once{}
+	t.Cleanup(func() { marksOnce = sync.Once{} })
+}
+
+func TestMarksFollowNoColor(t *testing.T) {
+	resetMarks(t)
+	t.Setenv("NO_COLOR", "1")
+
+	m := mark()
+	if m.Fail != "[fail] " || m.Warn != "[!] " || m.OK != "[ok] " {
+		t.Errorf("mono marks = %q %q %q", m.Fail, m.Warn, m.OK)
+	}
+	if got := icon("📡"); got != "" {
+		t.Errorf("icon in an ASCII theme = %q, want none", got)
+	}
+}
+
+func TestMarksDefaultTheme(t *testing.T) {
+	resetMarks(t)
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	m := mark()
+	for sym, got := range map[string]string{"✗": m.Fail, "⚠": m.Warn, "✓": m.OK} {
+		if !strings.Contains(got, sym) || !strings.HasSuffix(got, " ") {
+			t.Errorf("mark for %s = %q", sym, got)
+		}
+	}
+	if got := icon("📡"); got != "📡 " {
+		t.Errorf("icon = %q", got)
+	}
+}
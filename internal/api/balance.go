@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// balanceOfSelector is the first four bytes of keccak256("balanceOf(address)")
+const balanceOfSelector = "70a08231"
+
+const (
+	usdcUnit       = 1e6
+	piconeroPerXMR = 1e12
+)
+
+var readClient = &http.Client{Timeout: 10 * time.Second}
+
+// MoneroRPCURL is the wallet-rpc endpoint saved by 'entropy login xmr'
+func MoneroRPCURL() string {
+	rpcURL, _ := keyring.Get(config.KeyringService, config.UserAccount+"-xmr-rpc")
+	if rpcURL == "" {
+		rpcURL = config.DefaultMoneroRPC
+	}
+	return rpcURL
+}
+
+// jsonRPC posts a JSON-RPC 2.0 call and decodes its result into out
+func jsonRPC(ctx context.Context, url, method string, params, out interface{}) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "0",
+		"method":  method,
+		"params":  params,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := readClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("%s: bad response (%d)", method, resp.StatusCode)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s: %s", method, rpcResp.Error.Message)
+	}
+	return json.Unmarshal(rpcResp.Result, out)
+}
+
+// USDCBalance reads an ERC-20 balance with eth_call balanceOf(owner)
+func USDCBalance(ctx context.Context, rpcURL, contract, owner string) (float64, error) {
+	addr := strings.TrimPrefix(strings.ToLower(owner), "0x")
+	if len(addr) != 40 {
+		return 0, fmt.Errorf("invalid EVM address %q", owner)
+	}
+	call := map[string]string{
+		"to":   contract,
+		"data": "0x" + balanceOfSelector + strings.Repeat("0", 24) + addr,
+	}
+
+	var result string
+	if err := jsonRPC(ctx, rpcURL, "eth_call", []interface{}{call, "latest"}, &result); err != nil {
+		return 0, err
+	}
+	raw, ok := new(big.Int).SetString(strings.TrimPrefix(result, "0x"), 16)
+	if !ok {
+		return 0, fmt.Errorf("eth_call: unexpected result %q", result)
+	}
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), big.NewFloat(usdcUnit)).Float64()
+	return v, nil
+}
+
+// MoneroBalance returns the unlocked balance of the wallet-rpc account in XMR
+func MoneroBalance(ctx context.Context, rpcURL string) (float64, error) {
+	var result struct {
+		UnlockedBalance uint64 `json:"unlocked_balance"`
+	}
+	params := map[string]interface{}{"account_index": 0}
+	if err := jsonRPC(ctx, rpcURL, "get_balance", params, &result); err != nil {
+		return 0, err
+	}
+	return float64(result.UnlockedBalance) / piconeroPerXMR, nil
+}
+
+// Stats is the public /stats payload: status, active_vms, cpu_usage, uptime
+type Stats map[string]interface{}
+
+// FetchStats queries the free /stats endpoint; it does not need a paying client
+func FetchStats(ctx context.Context) (Stats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.BaseURL+"/stats", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := readClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server error (%d)", resp.StatusCode)
+	}
+	var stats Stats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -21,6 +22,18 @@ import (
 type Client struct {
 	HTTPClient *http.Client
 	PayerID    string
+
+	// OnSettlement, if set, is called for every request that was paid for
+	OnSettlement func(Settlement)
+	// OnProgress, if set, is called as a request moves through the x402
+	// handshake. It runs on the requesting goroutine and must not block.
+	OnProgress func(Progress)
+
+	x402 settleDecoder
+}
+
+type settleDecoder interface {
+	GetPaymentSettleResponse(headers map[string]string) (*x402.SettleResponse, error)
 }
 
 type RemoteVM struct {
@@ -57,19 +70,14 @@ func NewClient(preference string) (*Client, error) {
 	// 1. Check for EVM Identity
 	if privKey, err := keyring.Get(config.KeyringService, config.UserAccount+"-key"); err == nil {
 		signer, _ := evmsigners.NewClientSignerFromPrivateKey(privKey)
-		clientCore.Register("eip155:*", evm.NewExactEvmScheme(signer))
+		clientCore.Register("eip155:*", tracedScheme{evm.NewExactEvmScheme(signer)})
 		finalPayerID = signer.Address()
 	}
 
 	// 2. Check for Monero Identity
 	// We'll store the primary address in the keyring during 'entropy login xmr'
 	if xmrAddr, err := keyring.Get(config.KeyringService, config.UserAccount+"-xmr-addr"); err == nil {
-		rpcURL, _ := keyring.Get(config.KeyringService, config.UserAccount+"-xmr-rpc")
-		if rpcURL == "" {
-			rpcURL = config.DefaultMoneroRPC
-		}
-
-		clientCore.Register("monero:*", &MoneroClientScheme{RPCURL: rpcURL})
+		clientCore.Register("monero:*", tracedScheme{&MoneroClientScheme{RPCURL: MoneroRPCURL()}})
 
 		// If we don't have an EVM address, use the derived Monero ID
 		if finalPayerID == "" {
@@ -81,20 +89,31 @@ func NewClient(preference string) (*Client, error) {
 		return nil, fmt.Errorf("no identity linked: run 'entropy login' first")
 	}
 
+	httpCore := x402http.Newx402HTTPClient(clientCore)
 	wrappedClient := x402http.WrapHTTPClientWithPayment(
-		&http.Client{Timeout: 150 * time.Second},
-		x402http.Newx402HTTPClient(clientCore),
+		&http.Client{Timeout: 150 * time.Second, Transport: progressTransport{http.DefaultTransport}},
+		httpCore,
 	)
 
 	return &Client{
 		HTTPClient: wrappedClient,
 		PayerID:    finalPayerID,
+		x402:       httpCore,
 	}, nil
 }
 
 // DoRequest is a helper to perform requests with standard Entropy headers
 func (c *Client) DoRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	resp, _, err := c.do(ctx, method, path, body, headers)
+	return resp, err
+}
+
+// do is DoRequest that also returns what the request paid, if anything
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, *Settlement, error) {
 	fullURL := config.BaseURL + path
+	// Query strings may carry SSH keys; only the route is logged
+	route := strings.SplitN(path, "?", 2)[0]
+	ctx, trace := withTrace(ctx, route, c.OnProgress)
 
 	var req *http.Request
 	var err error
@@ -102,11 +121,11 @@ func (c *Client) DoRequest(ctx context.Context, method, path string, body io.Rea
 	if body != nil {
 		bodyBytes, err := io.ReadAll(body)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		req, err = http.NewRequestWithContext(ctx, method, fullURL, bytes.NewReader(bodyBytes))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		req.GetBody = func() (io.ReadCloser, error) {
 			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
@@ -114,7 +133,7 @@ func (c *Client) DoRequest(ctx context.Context, method, path string, body io.Rea
 	} else {
 		req, err = http.NewRequestWithContext(ctx, method, fullURL, http.NoBody)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -125,5 +144,18 @@ func (c *Client) DoRequest(ctx context.Context, method, path string, body io.Rea
 		req.Header.Set(k, v)
 	}
 
-	return c.HTTPClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		slog.Error("orchestrator request failed", "method", method, "path", route, "err", err)
+		return nil, nil, err
+	}
+	if resp.StatusCode >= 400 {
+		slog.Warn("orchestrator returned an error", "method", method, "path", route, "status", resp.StatusCode)
+	}
+
+	s := c.settlementFor(resp, path, trace)
+	if s != nil && c.OnSettlement != nil {
+		c.OnSettlement(*s)
+	}
+	return resp, s, nil
 }
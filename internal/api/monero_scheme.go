@@ -54,6 +54,7 @@ func (s *MoneroClientScheme) CreatePaymentPayload(ctx context.Context, req x402.
 	if rpcResp.Error != nil {
 		return x402.PaymentPayload{}, fmt.Errorf("xmr transfer failed: %v", rpcResp.Error)
 	}
+	traceFrom(ctx).emit(StageMoneroBroadcast, rpcResp.Result.TxHash)
 
 	return x402.PaymentPayload{
 		X402Version: 2,
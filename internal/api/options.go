@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+)
+
+// Spec is a hardware attribute the orchestrator may send as a number or a string
+type Spec string
+
+func (s *Spec) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = Spec(str)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*s = Spec(n)
+	return nil
+}
+
+// Price is an hourly USD rate, sent either as a JSON number or a numeric string
+type Price float64
+
+func (p *Price) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseFloat(strings.Trim(string(b), `"`), 64)
+	if err != nil {
+		return err
+	}
+	*p = Price(v)
+	return nil
+}
+
+type RegionPrice struct {
+	HourlyCost Price `json:"HourlyCost"`
+}
+
+type Tier struct {
+	CPU     Spec                   `json:"CPU"`
+	RAM     Spec                   `json:"RAM"`
+	Disk    Spec                   `json:"Disk"`
+	Regions map[string]RegionPrice `json:"Regions"`
+}
+
+// Options is the public resource manifest served by /options
+type Options struct {
+	Tiers   map[string]Tier `json:"tiers"`
+	Distros []string        `json:"distros"`
+	Regions []string        `json:"regions"`
+	Note    string          `json:"note"`
+
+	// FetchedAt is only set on manifests loaded from the offline cache
+	FetchedAt time.Time `json:"fetched_at,omitempty"`
+}
+
+func optionsCachePath() string {
+	return filepath.Join(config.Dir(), "cache", "options.json")
+}
+
+// LoadOptions fetches the manifest and refreshes the offline cache. When the
+// orchestrator is unreachable the cached copy is returned with FetchedAt set.
+func LoadOptions(ctx context.Context) (*Options, error) {
+	opts, err := FetchOptions(ctx)
+	if err == nil {
+		saveOptionsCache(opts)
+		return opts, nil
+	}
+
+	cached, cacheErr := cachedOptions()
+	if cacheErr != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+func saveOptionsCache(opts *Options) {
+	c := *opts
+	c.FetchedAt = time.Now()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(optionsCachePath()), 0700); err != nil {
+		return
+	}
+	os.WriteFile(optionsCachePath(), data, 0600)
+}
+
+func cachedOptions() (*Options, error) {
+	data, err := os.ReadFile(optionsCachePath())
+	if err != nil {
+		return nil, err
+	}
+	var opts Options
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// TierNames returns the tiers ordered from cheapest to most expensive
+func (o *Options) TierNames() []string {
+	names := make([]string, 0, len(o.Tiers))
+	for n := range o.Tiers {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ci, cj := o.Tiers[names[i]].minHourly(), o.Tiers[names[j]].minHourly()
+		if ci != cj {
+			return ci < cj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// RegionNames returns the regions a tier is offered in, alphabetically
+func (t Tier) RegionNames() []string {
+	names := make([]string, 0, len(t.Regions))
+	for n := range t.Regions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t Tier) minHourly() Price {
+	var min Price
+	for _, r := range t.Regions {
+		if min == 0 || r.HourlyCost < min {
+			min = r.HourlyCost
+		}
+	}
+	return min
+}
+
+// FetchOptions downloads the manifest. The endpoint is free, so no payment client is needed.
+func FetchOptions(ctx context.Context) (*Options, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", config.BaseURL+"/options", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("options: %s", resp.Status)
+	}
+
+	var opts Options
+	if err := json.NewDecoder(resp.Body).Decode(&opts); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// Quote estimates the lease cost of tier in region for a duration such as "24h"
+func (o *Options) Quote(tier, region, duration string) (float64, error) {
+	t, ok := o.Tiers[tier]
+	if !ok {
+		return 0, fmt.Errorf("unknown tier %q", tier)
+	}
+	r, ok := t.Regions[region]
+	if !ok {
+		return 0, fmt.Errorf("tier %s is not offered in %s", tier, region)
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", duration)
+	}
+	return float64(r.HourlyCost) * d.Hours(), nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+const manifest = `{
+	"tiers": {
+		"standard":  {"CPU": 2, "RAM": "4GB", "Disk": 40, "Regions": {"fsn1": {"HourlyCost": 0.02}, "ash": {"HourlyCost": "0.03"}}},
+		"eco-small": {"CPU": 1, "RAM": "2GB", "Disk": 20, "Regions": {"hel1": {"HourlyCost": 0.005}}},
+		"monster":   {"CPU": 16, "RAM": "64GB", "Disk": 320, "Regions": {"fsn1": {"HourlyCost": 0.4}}}
+	},
+	"distros": ["ubuntu-24.04"]
+}`
+
+func loadManifest(t *testing.T) *Options {
+	t.Helper()
+	var o Options
+	if err := json.Unmarshal([]byte(manifest), &o); err != nil {
+		t.Fatal(err)
+	}
+	return &o
+}
+
+func TestOptionsDecode(t *testing.T) {
+	o := loadManifest(t)
+	std := o.Tiers["standard"]
+	if std.CPU != "2" || std.RAM != "4GB" || std.Regions["ash"].HourlyCost != 0.03 {
+		t.Fatalf("decoded %+v", std)
+	}
+	if got, want := o.TierNames(), []string{"eco-small", "standard", "monster"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TierNames = %v, want %v", got, want)
+	}
+	if got, want := std.RegionNames(), []string{"ash", "fsn1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RegionNames = %v, want %v", got, want)
+	}
+}
+
+func TestSpecDecode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr bool
+	}{
+		{`4`, "4", false},
+		{`2.5`, "2.5", false},
+		{`"4GB"`, "4GB", false},
+		{`"40 GB \u00b7 NVMe"`, "40 GB · NVMe", false},
+		{`"say \"fast\""`, `say "fast"`, false},
+		{`null`, "", false},
+		{`{"size": 4}`, "", true},
+	}
+	for _, tt := range tests {
+		var got Spec
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Spec from %s = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	o := loadManifest(t)
+	tests := []struct {
+		tier, region, duration string
+		want                   float64
+		wantErr                bool
+	}{
+		{"standard", "fsn1", "1h", 0.02, false},
+		{"standard", "ash", "24h", 0.72, false},
+		{"eco-small", "hel1", "30m", 0.0025, false},
+		{"monster", "fsn1", "168h", 67.2, false},
+		{"unknown", "fsn1", "1h", 0, true},
+		{"eco-small", "fsn1", "1h", 0, true},
+		{"standard", "fsn1", "a while", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := o.Quote(tt.tier, tt.region, tt.duration)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Quote(%s, %s, %s) err = %v, wantErr %v", tt.tier, tt.region, tt.duration, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Quote(%s, %s, %s) = %v, want %v", tt.tier, tt.region, tt.duration, got, tt.want)
+		}
+	}
+}
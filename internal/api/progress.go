@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stage is one step of a paid request, reported through Client.OnProgress
+type Stage int
+
+const (
+	StagePaymentRequired     Stage = iota + 1 // orchestrator answered 402
+	StageRequirementSelected                  // a network/asset was chosen from the 402
+	StageMoneroBroadcast                      // wallet-rpc sent the XMR transfer
+	StagePayloadSigned                        // payment payload is ready
+	StageRetrying                             // request re-sent with the payment header
+	StageSettled                              // facilitator confirmed the settlement
+	StageVMAllocated                          // /provision returned a VM
+)
+
+var stageNames = map[Stage]string{
+	StagePaymentRequired:     "402 payment required",
+	StageRequirementSelected: "payment requirement selected",
+	StageMoneroBroadcast:     "Monero transfer broadcast",
+	StagePayloadSigned:       "payment payload signed",
+	StageRetrying:            "retrying with payment",
+	StageSettled:             "settlement confirmed",
+	StageVMAllocated:         "VM allocated",
+}
+
+func (s Stage) String() string {
+	if name, ok := stageNames[s]; ok {
+		return name
+	}
+	return "unknown stage"
+}
+
+// Progress is a stage reached by one DoRequest. Detail carries the network,
+// amount or transaction hash where there is one.
+type Progress struct {
+	Stage  Stage
+	Path   string
+	Detail string
+	At     time.Time
+}
+
+var waitingFor = map[Stage]string{
+	StagePaymentRequired:     "selecting a payment requirement",
+	StageRequirementSelected: "signing the payment",
+	StageMoneroBroadcast:     "preparing the payment payload",
+	StagePayloadSigned:       "sending the paid request",
+	StageRetrying:            "waiting for the facilitator to settle",
+	StageSettled:             "waiting for the orchestrator",
+	StageVMAllocated:         "saving to the local registry",
+}
+
+// Waiting describes what the client is waiting on once p has been reached
+func (p Progress) Waiting() string {
+	if p.Stage == StageRequirementSelected && strings.Contains(p.Detail, "monero") {
+		return "broadcasting the Monero transfer"
+	}
+	return waitingFor[p.Stage]
+}
+
+func (t *paymentTrace) emit(stage Stage, detail string) {
+	if t == nil || t.report == nil {
+		return
+	}
+	t.report(Progress{Stage: stage, Path: t.path, Detail: detail, At: time.Now()})
+}
+
+// progress reports a stage that is known only after DoRequest returns
+func (c *Client) progress(stage Stage, path, detail string) {
+	if c.OnProgress != nil {
+		c.OnProgress(Progress{Stage: stage, Path: path, Detail: detail, At: time.Now()})
+	}
+}
+
+// progressTransport sits under the x402 round tripper, so it sees both the
+// unpaid request that draws the 402 and the paid retry
+type progressTransport struct {
+	base http.RoundTripper
+}
+
+func (p progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := traceFrom(req.Context())
+	if req.Header.Get("PAYMENT-SIGNATURE") != "" || req.Header.Get("X-PAYMENT") != "" {
+		t.emit(StageRetrying, "")
+	}
+	resp, err := p.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusPaymentRequired {
+		t.emit(StagePaymentRequired, "")
+	}
+	return resp, err
+}
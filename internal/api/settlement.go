@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	x402 "github.com/coinbase/x402/go"
+)
+
+// Settlement is a payment the orchestrator accepted for a single request
+type Settlement struct {
+	Network string    `json:"network"`
+	Asset   string    `json:"asset"`
+	Amount  string    `json:"amount"` // atomic units of Asset
+	TxHash  string    `json:"tx_hash"`
+	Payer   string    `json:"payer"`
+	Path    string    `json:"path"`
+	At      time.Time `json:"at"`
+}
+
+// Value converts Amount into whole units of the settled currency
+func (s Settlement) Value() (float64, string) {
+	amount, ok := new(big.Float).SetString(s.Amount)
+	if !ok {
+		return 0, s.Asset
+	}
+
+	net := strings.ToLower(s.Network)
+	switch {
+	case strings.Contains(net, "monero"):
+		v, _ := new(big.Float).Quo(amount, big.NewFloat(1e12)).Float64()
+		return v, "XMR"
+	case strings.HasPrefix(net, "eip155"):
+		v, _ := new(big.Float).Quo(amount, big.NewFloat(1e6)).Float64()
+		return v, "USDC"
+	}
+	v, _ := amount.Float64()
+	return v, s.Asset
+}
+
+// paymentTrace follows one DoRequest through the x402 round tripper
+type paymentTrace struct {
+	mu          sync.Mutex
+	requirement *x402.PaymentRequirements
+
+	path   string
+	report func(Progress)
+}
+
+type traceKey struct{}
+
+func withTrace(ctx context.Context, path string, report func(Progress)) (context.Context, *paymentTrace) {
+	t := &paymentTrace{path: path, report: report}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+func traceFrom(ctx context.Context) *paymentTrace {
+	t, _ := ctx.Value(traceKey{}).(*paymentTrace)
+	return t
+}
+
+func (t *paymentTrace) selected() *x402.PaymentRequirements {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requirement
+}
+
+// tracedScheme records which requirement was paid so the settlement can be priced
+type tracedScheme struct {
+	x402.SchemeNetworkClient
+}
+
+func (s tracedScheme) CreatePaymentPayload(ctx context.Context, req x402.PaymentRequirements) (x402.PaymentPayload, error) {
+	value, unit := Settlement{Network: req.Network, Asset: req.Asset, Amount: req.Amount}.Value()
+	traceFrom(ctx).emit(StageRequirementSelected, fmt.Sprintf("%g %s on %s", value, unit, req.Network))
+
+	payload, err := s.SchemeNetworkClient.CreatePaymentPayload(ctx, req)
+	if err != nil {
+		slog.Error("x402 payment payload failed", "scheme", req.Scheme, "network", req.Network, "err", err)
+	} else {
+		slog.Info("x402 payment requirement selected", "scheme", req.Scheme, "network", req.Network, "asset", req.Asset, "amount", req.Amount)
+		traceFrom(ctx).emit(StagePayloadSigned, "")
+	}
+	if t := traceFrom(ctx); t != nil && err == nil {
+		t.mu.Lock()
+		t.requirement = &req
+		t.mu.Unlock()
+	}
+	return payload, err
+}
+
+// settlementFor builds the Settlement for a paid response, or nil if nothing was paid
+func (c *Client) settlementFor(resp *http.Response, path string, t *paymentTrace) *Settlement {
+	req := t.selected()
+	if req == nil || resp.StatusCode >= 300 {
+		return nil
+	}
+
+	s := &Settlement{
+		Network: req.Network,
+		Asset:   req.Asset,
+		Amount:  req.Amount,
+		Payer:   c.PayerID,
+		Path:    strings.SplitN(path, "?", 2)[0],
+		At:      time.Now(),
+	}
+
+	headers := make(map[string]string)
+	for k, v := range resp.Header {
+		if len(v) > 0 {
+			headers[k] = v[0]
+		}
+	}
+	if settled, err := c.x402.GetPaymentSettleResponse(headers); err == nil && settled != nil {
+		s.TxHash = settled.Transaction
+		if settled.Network != "" {
+			s.Network = string(settled.Network)
+		}
+		if settled.Payer != "" {
+			s.Payer = settled.Payer
+		}
+	}
+
+	value, unit := s.Value()
+	t.emit(StageSettled, s.TxHash)
+	slog.Info("payment settled", "network", s.Network, "amount", fmt.Sprintf("%g %s", value, unit), "tx_hash", s.TxHash, "path", s.Path)
+	return s
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSettlementValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         Settlement
+		wantValue float64
+		wantUnit  string
+	}{
+		{"base usdc", Settlement{Network: "eip155:8453", Asset: "0x833589f", Amount: "1000"}, 0.001, "USDC"},
+		{"sepolia usdc", Settlement{Network: "EIP155:84532", Asset: "0x036c", Amount: "2500000"}, 2.5, "USDC"},
+		{"monero", Settlement{Network: "monero-mainnet", Asset: "xmr", Amount: "2000000000"}, 0.002, "XMR"},
+		{"unknown network", Settlement{Network: "solana", Asset: "SOL", Amount: "3"}, 3, "SOL"},
+		{"unparsable amount", Settlement{Network: "eip155:8453", Asset: "0x833589f", Amount: "n/a"}, 0, "0x833589f"},
+	}
+	for _, tt := range tests {
+		v, unit := tt.s.Value()
+		if math.Abs(v-tt.wantValue) > 1e-12 || unit != tt.wantUnit {
+			t.Errorf("%s: Value() = %v %s, want %v %s", tt.name, v, unit, tt.wantValue, tt.wantUnit)
+		}
+	}
+}
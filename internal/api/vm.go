@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type RenewResponse struct {
+	Status    string `json:"status"`
+	NewExpiry string `json:"new_expiry"`
+	Message   string `json:"message"`
+
+	// Settlement is the payment for this renewal, filled in by the client
+	Settlement *Settlement `json:"settlement,omitempty"`
+}
+
+// ExpiresAt parses NewExpiry, which the orchestrator sends as RFC 3339
+func (r RenewResponse) ExpiresAt() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, r.NewExpiry)
+	return t, err == nil
+}
+
+// Renew extends the lease of vmName by duration, paying through x402
+func (c *Client) Renew(ctx context.Context, vmName, duration string) (*RenewResponse, error) {
+	params := url.Values{}
+	params.Add("vm_name", vmName)
+	params.Add("duration", duration)
+
+	headers := map[string]string{"X-VM-NAME": vmName, "X-VM-DURATION": duration}
+	resp, settlement, err := c.do(ctx, "POST", "/renew?"+params.Encode(), nil, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var res RenewResponse
+	json.Unmarshal(body, &res)
+	res.Settlement = settlement
+	return &res, nil
+}
+
+type ProvisionResponse struct {
+	Status string `json:"status"`
+	VM     struct {
+		ProviderID int64     `json:"ProviderID"`
+		Name       string    `json:"Name"`
+		IP         string    `json:"IP"`
+		Tier       string    `json:"Tier"`
+		Region     string    `json:"Region"`
+		Password   string    `json:"Password"`
+		ExpiresAt  time.Time `json:"ExpiresAt"`
+	} `json:"vm"`
+
+	// Settlement is the payment for this VM, filled in by the client
+	Settlement *Settlement `json:"settlement,omitempty"`
+}
+
+// ProvisionParams describes a lease request. SSHKey is the public key text, not a path.
+type ProvisionParams struct {
+	Tier     string
+	Distro   string
+	Region   string
+	Duration string
+	SSHKey   string
+}
+
+func (p ProvisionParams) headers() map[string]string {
+	return map[string]string{
+		"X-VM-TIER":     p.Tier,
+		"X-VM-DURATION": p.Duration,
+		"X-VM-REGION":   p.Region,
+	}
+}
+
+// Validate runs the free eligibility check. A nil error means the orchestrator
+// did not refuse; transport errors are ignored so an unreachable /validate never blocks provisioning.
+func (c *Client) Validate(ctx context.Context, p ProvisionParams) error {
+	resp, err := c.DoRequest(ctx, "POST", "/validate", nil, p.headers())
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("eligibility check failed: %s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Provision pays for and allocates a VM
+func (c *Client) Provision(ctx context.Context, p ProvisionParams) (*ProvisionResponse, error) {
+	params := url.Values{}
+	params.Add("tier", p.Tier)
+	params.Add("distro", p.Distro)
+	params.Add("region", p.Region)
+	params.Add("duration", p.Duration)
+	params.Add("ssh_key", p.SSHKey)
+
+	resp, settlement, err := c.do(ctx, "POST", "/provision?"+params.Encode(), nil, p.headers())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result ProvisionResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse server response: %w", err)
+	}
+	result.Settlement = settlement
+	c.progress(StageVMAllocated, "/provision", result.VM.Name)
+	return &result, nil
+}
+
+// Destroy tears down vmName. Any non-200 answer is an error, so callers only
+// forget a VM the orchestrator has actually released.
+func (c *Client) Destroy(ctx context.Context, vmName string) error {
+	params := url.Values{}
+	params.Add("vm_name", vmName)
+
+	headers := map[string]string{"X-VM-NAME": vmName}
+	resp, err := c.DoRequest(ctx, "DELETE", "/provision?"+params.Encode(), nil, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// List returns the VMs the orchestrator holds for c.PayerID. Like every
+// /list call it is a paid request.
+func (c *Client) List(ctx context.Context) (*ListResponse, error) {
+	resp, err := c.DoRequest(ctx, "GET", "/list", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var list ListResponse
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid /list response: %w", err)
+	}
+	return &list, nil
+}
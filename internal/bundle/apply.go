@@ -0,0 +1,345 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/zalando/go-keyring"
+	"gorm.io/gorm"
+)
+
+// Strategy decides what happens when an imported node collides with a local
+// one by ProviderID or alias
+type Strategy string
+
+const (
+	// Merge updates the local row for the same ProviderID (later lease wins,
+	// empty fields are filled) and renames incoming aliases that are taken
+	Merge Strategy = "merge"
+	// Skip keeps the local row and drops the incoming one
+	Skip Strategy = "skip"
+	// Overwrite replaces every colliding local row with the incoming one
+	Overwrite Strategy = "overwrite"
+)
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(strings.ToLower(s)); st {
+	case Merge, Skip, Overwrite:
+		return st, nil
+	}
+	return "", fmt.Errorf("unknown strategy %q (merge, skip or overwrite)", s)
+}
+
+// Report is what Apply did, by alias
+type Report struct {
+	Added    []string          `json:"added"`
+	Merged   []string          `json:"merged"`
+	Replaced []string          `json:"replaced"`
+	Skipped  []string          `json:"skipped"`
+	Renamed  map[string]string `json:"renamed"` // incoming alias -> local alias
+	Grants   int               `json:"grants"`
+	Payments int               `json:"payments"`
+	Keys     int               `json:"keys"`
+	// RenamedKeys are key files that differed from a local file of the same name
+	RenamedKeys []string `json:"renamed_keys,omitempty"`
+	Identity    []string `json:"identity,omitempty"` // keyring entries restored
+	// UnlinkedEVM is a bundled EVM address left out because this machine
+	// holds no EVM key to sign for it
+	UnlinkedEVM string   `json:"unlinked_evm,omitempty"`
+	External    []string `json:"external,omitempty"`
+}
+
+type applier struct {
+	b        *Bundle
+	strategy Strategy
+	rep      *Report
+	keys     map[string]string // source key base name -> local base name
+	stamp    string
+}
+
+// Apply imports a bundle into the local registry and keys directory. Rows are
+// written in one transaction; key files are restored only for nodes that are
+// actually imported.
+func Apply(b *Bundle, strategy Strategy) (*Report, error) {
+	a := &applier{
+		b:        b,
+		strategy: strategy,
+		rep:      &Report{Renamed: make(map[string]string), External: b.External},
+		keys:     make(map[string]string),
+		stamp:    time.Now().Format("20060102-150405"),
+	}
+
+	accepted := make(map[int64]bool)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, in := range b.VMs {
+			ok, err := a.importVM(tx, in)
+			if err != nil {
+				return fmt.Errorf("%s: %w", in.Alias, err)
+			}
+			accepted[in.ProviderID] = ok
+		}
+		if err := a.importGrants(tx, accepted); err != nil {
+			return err
+		}
+		return a.importPayments(tx, accepted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Identity != nil {
+		a.restoreIdentity(*b.Identity)
+	}
+	sshmgr.SyncConfig()
+	slog.Info("bundle imported", "strategy", strategy, "added", len(a.rep.Added), "merged", len(a.rep.Merged),
+		"replaced", len(a.rep.Replaced), "skipped", len(a.rep.Skipped), "renamed", len(a.rep.Renamed))
+	return a.rep, nil
+}
+
+func (a *applier) importVM(tx *gorm.DB, in db.LocalVM) (bool, error) {
+	in.ID = 0
+	var byID, byAlias db.LocalVM
+	hasID := tx.Where("provider_id = ?", in.ProviderID).Limit(1).Find(&byID).RowsAffected > 0
+	hasAlias := tx.Where("alias = ?", in.Alias).Limit(1).Find(&byAlias).RowsAffected > 0
+
+	switch {
+	case !hasID && !hasAlias:
+		if err := a.create(tx, &in); err != nil {
+			return false, err
+		}
+		a.rep.Added = append(a.rep.Added, in.Alias)
+
+	case a.strategy == Skip:
+		a.rep.Skipped = append(a.rep.Skipped, in.Alias)
+		return false, nil
+
+	case a.strategy == Overwrite:
+		for _, local := range []db.LocalVM{byID, byAlias} {
+			if local.ID == 0 {
+				continue
+			}
+			if err := tx.Where("provider_id = ?", local.ProviderID).Delete(&db.AccessGrant{}).Error; err != nil {
+				return false, err
+			}
+			if err := tx.Where("provider_id = ?", local.ProviderID).Delete(&db.Payment{}).Error; err != nil {
+				return false, err
+			}
+			if err := tx.Delete(&db.LocalVM{}, local.ID).Error; err != nil {
+				return false, err
+			}
+		}
+		if err := a.create(tx, &in); err != nil {
+			return false, err
+		}
+		a.rep.Replaced = append(a.rep.Replaced, in.Alias)
+
+	case hasID:
+		if err := a.merge(tx, byID, in); err != nil {
+			return false, err
+		}
+		a.rep.Merged = append(a.rep.Merged, byID.Alias)
+
+	default:
+		// Same alias, different node: both are kept under distinct names
+		original := in.Alias
+		in.Alias = freeAlias(tx, original)
+		if err := a.create(tx, &in); err != nil {
+			return false, err
+		}
+		a.rep.Renamed[original] = in.Alias
+	}
+	return true, nil
+}
+
+func (a *applier) create(tx *gorm.DB, vm *db.LocalVM) error {
+	path, err := a.restoreKey(vm.SSHKeyPath)
+	if err != nil {
+		return err
+	}
+	vm.SSHKeyPath = path
+	return tx.Create(vm).Error
+}
+
+// merge folds in into local: the later lease wins and empty fields are filled.
+// The local alias is kept.
+func (a *applier) merge(tx *gorm.DB, local, in db.LocalVM) error {
+	if in.ExpiresAt.After(local.ExpiresAt) {
+		local.ExpiresAt = in.ExpiresAt
+		if in.IP != "" {
+			local.IP = in.IP
+		}
+	}
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&local.ServerName, in.ServerName)
+	fill(&local.IP, in.IP)
+	fill(&local.Region, in.Region)
+	fill(&local.Tier, in.Tier)
+	fill(&local.OwnerWallet, in.OwnerWallet)
+
+	if _, err := os.Stat(sshmgr.PrivateKeyPath(local.SSHKeyPath)); local.SSHKeyPath == "" || err != nil {
+		path, err := a.restoreKey(in.SSHKeyPath)
+		if err != nil {
+			return err
+		}
+		local.SSHKeyPath = path
+	}
+	return tx.Save(&local).Error
+}
+
+func (a *applier) importGrants(tx *gorm.DB, accepted map[int64]bool) error {
+	for _, g := range a.b.Grants {
+		if !accepted[g.ProviderID] {
+			continue
+		}
+		var count int64
+		tx.Model(&db.AccessGrant{}).Where("provider_id = ? AND fingerprint = ?", g.ProviderID, g.Fingerprint).Count(&count)
+		if count > 0 {
+			continue
+		}
+		g.ID = 0
+		if err := tx.Create(&g).Error; err != nil {
+			return err
+		}
+		a.rep.Grants++
+	}
+	return nil
+}
+
+// importPayments adds the cost history of imported nodes. A payment already
+// recorded here, matched by transaction hash or time, is not counted twice.
+func (a *applier) importPayments(tx *gorm.DB, accepted map[int64]bool) error {
+	for _, p := range a.b.Payments {
+		if !accepted[p.ProviderID] {
+			continue
+		}
+		q := tx.Model(&db.Payment{}).Where("provider_id = ? AND kind = ?", p.ProviderID, p.Kind)
+		if p.TxHash != "" {
+			q = q.Where("tx_hash = ?", p.TxHash)
+		} else {
+			q = q.Where("paid_at = ?", p.PaidAt)
+		}
+		var count int64
+		q.Count(&count)
+		if count > 0 {
+			continue
+		}
+		p.ID = 0
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+		a.rep.Payments++
+	}
+	return nil
+}
+
+// restoreKey writes the keypair behind a source key path into the local keys
+// directory and returns the path to store. Keys that were outside the source
+// keys directory were not exported and keep their path.
+func (a *applier) restoreKey(srcPath string) (string, error) {
+	if srcPath == "" || filepath.Dir(srcPath) != a.b.KeysDir {
+		return srcPath, nil
+	}
+	name := filepath.Base(srcPath)
+	base := strings.TrimSuffix(name, ".pub")
+	suffix := strings.TrimPrefix(name, base)
+
+	local, ok := a.keys[base]
+	if !ok {
+		var err error
+		if local, err = a.writePair(base); err != nil {
+			return "", err
+		}
+		a.keys[base] = local
+	}
+	return filepath.Join(sshmgr.KeysDir(), local+suffix), nil
+}
+
+// writePair restores base and base.pub. If a local file of the same name has
+// different content the pair is written under a new name instead.
+func (a *applier) writePair(base string) (string, error) {
+	var files []KeyFile
+	for _, k := range a.b.Keys {
+		if k.Name == base || k.Name == base+".pub" {
+			files = append(files, k)
+		}
+	}
+	if len(files) == 0 {
+		return base, nil
+	}
+	if err := os.MkdirAll(sshmgr.KeysDir(), 0700); err != nil {
+		return "", err
+	}
+
+	target := base
+	for _, k := range files {
+		existing, err := os.ReadFile(filepath.Join(sshmgr.KeysDir(), k.Name))
+		if err == nil && !bytes.Equal(existing, k.Data) {
+			target = base + ".imported-" + a.stamp
+			a.rep.RenamedKeys = append(a.rep.RenamedKeys, base)
+			break
+		}
+	}
+
+	for _, k := range files {
+		path := filepath.Join(sshmgr.KeysDir(), target+strings.TrimPrefix(k.Name, base))
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, k.Data) {
+			continue
+		}
+		mode := os.FileMode(0600)
+		if strings.HasSuffix(path, ".pub") {
+			mode = 0644
+		}
+		if err := os.WriteFile(path, k.Data, mode); err != nil {
+			return "", err
+		}
+		a.rep.Keys++
+	}
+	return target, nil
+}
+
+// restoreIdentity fills keyring entries that are not already set; an
+// existing login is never replaced
+func (a *applier) restoreIdentity(id Identity) {
+	for suffix, v := range map[string]string{"-addr": id.EVMAddress, "-xmr-addr": id.XMRAddress, "-xmr-rpc": id.XMRRPC} {
+		if v == "" {
+			continue
+		}
+		if _, err := keyring.Get(config.KeyringService, config.UserAccount+suffix); err == nil {
+			continue
+		}
+		// An address without its key would read as a linked wallet that cannot pay
+		if suffix == "-addr" {
+			if _, err := keyring.Get(config.KeyringService, config.UserAccount+"-key"); err != nil {
+				a.rep.UnlinkedEVM = v
+				continue
+			}
+		}
+		if err := keyring.Set(config.KeyringService, config.UserAccount+suffix, v); err == nil {
+			a.rep.Identity = append(a.rep.Identity, strings.TrimPrefix(suffix, "-"))
+		}
+	}
+}
+
+func freeAlias(tx *gorm.DB, alias string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", alias, i)
+		var count int64
+		tx.Model(&db.LocalVM{}).Where("alias = ?", candidate).Count(&count)
+		if count == 0 {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package bundle moves the local registry and its SSH keys between machines
+// as a single age-encrypted file.
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"github.com/zalando/go-keyring"
+)
+
+const formatVersion = 1
+
+// Bundle is the plaintext inside an export
+type Bundle struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	KeysDir   string           `json:"keys_dir"` // source KeysDir, used to remap key paths
+	VMs       []db.LocalVM     `json:"vms"`
+	Grants    []db.AccessGrant `json:"grants"`
+	Payments  []db.Payment     `json:"payments,omitempty"`
+	Keys      []KeyFile        `json:"keys"`
+	Identity  *Identity        `json:"identity,omitempty"`
+
+	// External lists nodes whose key lives outside KeysDir and was not copied
+	External []string `json:"external,omitempty"`
+}
+
+// KeyFile is one file from the entropy keys directory
+type KeyFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// Identity is the non-secret half of a login. Private keys are never exported.
+type Identity struct {
+	EVMAddress string `json:"evm_address,omitempty"`
+	XMRAddress string `json:"xmr_address,omitempty"`
+	XMRRPC     string `json:"xmr_rpc,omitempty"`
+}
+
+// Build collects the registry and every file under the keys directory
+func Build(withIdentity bool) (*Bundle, error) {
+	b := &Bundle{Version: formatVersion, CreatedAt: time.Now(), KeysDir: sshmgr.KeysDir()}
+	if err := db.DB.Order("alias").Find(&b.VMs).Error; err != nil {
+		return nil, err
+	}
+	if err := db.DB.Find(&b.Grants).Error; err != nil {
+		return nil, err
+	}
+	if err := db.DB.Order("paid_at").Find(&b.Payments).Error; err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(b.KeysDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(b.KeysDir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		b.Keys = append(b.Keys, KeyFile{Name: e.Name(), Data: data})
+	}
+
+	for _, vm := range b.VMs {
+		if vm.SSHKeyPath != "" && filepath.Dir(vm.SSHKeyPath) != b.KeysDir {
+			b.External = append(b.External, vm.Alias)
+		}
+	}
+
+	if withIdentity {
+		get := func(suffix string) string {
+			v, _ := keyring.Get(config.KeyringService, config.UserAccount+suffix)
+			return v
+		}
+		b.Identity = &Identity{EVMAddress: get("-addr"), XMRAddress: get("-xmr-addr"), XMRRPC: get("-xmr-rpc")}
+	}
+	return b, nil
+}
+
+// ParseRecipient accepts an age X25519 recipient (age1...) or an SSH public key
+func ParseRecipient(s string) (age.Recipient, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "age1") {
+		return age.ParseX25519Recipient(s)
+	}
+	return agessh.ParseRecipient(s)
+}
+
+// ParseIdentityFile reads an age identity file or an unencrypted SSH private key
+func ParseIdentityFile(path string) ([]age.Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ids, err := age.ParseIdentities(bytes.NewReader(data)); err == nil {
+		return ids, nil
+	}
+	id, err := agessh.ParseIdentity(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither an age identity nor an unencrypted SSH key: %w", path, err)
+	}
+	return []age.Identity{id}, nil
+}
+
+// Write encrypts the bundle to w for the given recipients
+func (b *Bundle) Write(w io.Writer, recipients ...age.Recipient) error {
+	enc, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(enc).Encode(b); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Read decrypts a bundle written by Write
+func Read(r io.Reader, identities ...age.Identity) (*Bundle, error) {
+	dec, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return nil, err
+	}
+	var b Bundle
+	if err := json.NewDecoder(dec).Decode(&b); err != nil {
+		return nil, fmt.Errorf("corrupt bundle: %w", err)
+	}
+	if b.Version > formatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than this build supports", b.Version)
+	}
+	return &b, nil
+}
+
+// IsPassphraseProtected reports whether the file at path was encrypted with a
+// passphrase rather than for a recipient key
+func IsPassphraseProtected(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(f, header)
+	return bytes.Contains(header[:n], []byte("\n-> scrypt ")), nil
+}
@@ -0,0 +1,290 @@
+package bundle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"filippo.io/age"
+	"github.com/zalando/go-keyring"
+)
+
+// registry opens a migrated registry under a temp HOME
+func registry(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+}
+
+var lease = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func seed(t *testing.T, rows ...any) {
+	t.Helper()
+	for _, r := range rows {
+		if err := db.DB.Create(r).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func aliases(t *testing.T) []string {
+	t.Helper()
+	var vms []db.LocalVM
+	db.DB.Order("alias").Find(&vms)
+	out := make([]string, len(vms))
+	for i, vm := range vms {
+		out[i] = vm.Alias
+	}
+	return out
+}
+
+func incoming() *Bundle {
+	return &Bundle{
+		Version: formatVersion,
+		KeysDir: "/elsewhere/keys",
+		VMs: []db.LocalVM{
+			{ProviderID: 1, Alias: "web", IP: "203.0.113.1", Region: "fsn1", Tier: "standard", ExpiresAt: lease.Add(48 * time.Hour)},
+			{ProviderID: 2, Alias: "db", IP: "203.0.113.2", ExpiresAt: lease},
+			{ProviderID: 3, Alias: "new", IP: "203.0.113.3", ExpiresAt: lease},
+		},
+		Grants: []db.AccessGrant{
+			{ProviderID: 1, Grantee: "alice", Fingerprint: "SHA256:a"},
+			{ProviderID: 2, Grantee: "bob", Fingerprint: "SHA256:b"},
+		},
+		Payments: []db.Payment{
+			{ProviderID: 1, Kind: "provision", Value: 0.24, Unit: "USDC", TxHash: "0x1"},
+			{ProviderID: 1, Kind: "renew", Value: 0.24, Unit: "USDC", TxHash: "0x2"},
+			{ProviderID: 3, Kind: "provision", Value: 0.1, Unit: "USDC", TxHash: "0x3"},
+		},
+	}
+}
+
+// local has web under the same ProviderID with an older lease and an
+// unrelated node that already uses the alias db
+func local(t *testing.T) {
+	seed(t,
+		&db.LocalVM{ProviderID: 1, Alias: "web", IP: "198.51.100.1", ExpiresAt: lease},
+		&db.LocalVM{ProviderID: 9, Alias: "db", IP: "198.51.100.9", ExpiresAt: lease},
+		&db.AccessGrant{ProviderID: 1, Grantee: "alice", Fingerprint: "SHA256:a"},
+		&db.Payment{ProviderID: 1, Kind: "provision", Value: 0.24, Unit: "USDC", TxHash: "0x1"},
+		&db.Payment{ProviderID: 9, Kind: "provision", Value: 1, Unit: "USDC", TxHash: "0x9"},
+	)
+}
+
+func count(t *testing.T, model any, where string, args ...any) int64 {
+	t.Helper()
+	var n int64
+	db.DB.Model(model).Where(where, args...).Count(&n)
+	return n
+}
+
+// equal compares alias lists regardless of order
+func equal(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+func TestApplyMerge(t *testing.T) {
+	registry(t)
+	local(t)
+
+	rep, err := Apply(incoming(), Merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(rep.Added, []string{"new"}) || !equal(rep.Merged, []string{"web"}) || rep.Renamed["db"] != "db-2" {
+		t.Fatalf("report %+v", rep)
+	}
+	if got := aliases(t); !equal(got, []string{"db", "db-2", "new", "web"}) {
+		t.Errorf("aliases %v", got)
+	}
+
+	var web db.LocalVM
+	db.DB.Where("provider_id = 1").First(&web)
+	if !web.ExpiresAt.Equal(lease.Add(48*time.Hour)) || web.IP != "203.0.113.1" || web.Region != "fsn1" {
+		t.Errorf("merge did not take the later lease and fill fields: %+v", web)
+	}
+
+	// Duplicates are matched by fingerprint and tx hash
+	if rep.Grants != 1 || count(t, &db.AccessGrant{}, "provider_id = 1") != 1 {
+		t.Errorf("grants imported %d, web has %d", rep.Grants, count(t, &db.AccessGrant{}, "provider_id = 1"))
+	}
+	if rep.Payments != 2 || count(t, &db.Payment{}, "provider_id = 1") != 2 {
+		t.Errorf("payments imported %d, web has %d", rep.Payments, count(t, &db.Payment{}, "provider_id = 1"))
+	}
+	if count(t, &db.Payment{}, "provider_id = 9") != 1 {
+		t.Error("the unrelated local node lost its payments")
+	}
+}
+
+func TestApplySkip(t *testing.T) {
+	registry(t)
+	local(t)
+
+	rep, err := Apply(incoming(), Skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(rep.Added, []string{"new"}) || !equal(rep.Skipped, []string{"web", "db"}) {
+		t.Fatalf("report %+v", rep)
+	}
+
+	var web db.LocalVM
+	db.DB.Where("provider_id = 1").First(&web)
+	if !web.ExpiresAt.Equal(lease) || web.IP != "198.51.100.1" {
+		t.Errorf("skipped node was changed: %+v", web)
+	}
+	if count(t, &db.LocalVM{}, "provider_id = 2") != 0 || count(t, &db.AccessGrant{}, "provider_id = 2") != 0 {
+		t.Error("a skipped node or its grants were imported")
+	}
+	if rep.Payments != 1 || count(t, &db.Payment{}, "provider_id = 1") != 1 {
+		t.Errorf("payments of a skipped node were imported: %d", rep.Payments)
+	}
+}
+
+func TestApplyOverwrite(t *testing.T) {
+	registry(t)
+	local(t)
+
+	rep, err := Apply(incoming(), Overwrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(rep.Added, []string{"new"}) || !equal(rep.Replaced, []string{"web", "db"}) {
+		t.Fatalf("report %+v", rep)
+	}
+	if got := aliases(t); !equal(got, []string{"db", "new", "web"}) {
+		t.Errorf("aliases %v", got)
+	}
+	if count(t, &db.LocalVM{}, "provider_id = 9") != 0 || count(t, &db.Payment{}, "provider_id = 9") != 0 {
+		t.Error("the replaced node or its payments are still there")
+	}
+	if count(t, &db.Payment{}, "provider_id = 1") != 2 {
+		t.Errorf("web has %d payments, want the 2 from the bundle", count(t, &db.Payment{}, "provider_id = 1"))
+	}
+
+	// A second import of the same bundle changes nothing
+	rep, err = Apply(incoming(), Overwrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count(t, &db.Payment{}, "provider_id = 1") != 2 || count(t, &db.AccessGrant{}, "provider_id = 1") != 1 {
+		t.Error("re-importing duplicated grants or payments")
+	}
+}
+
+func TestApplyRestoresKeys(t *testing.T) {
+	registry(t)
+	b := incoming()
+	b.VMs = b.VMs[:1]
+	b.VMs[0].SSHKeyPath = "/elsewhere/keys/1_ed25519.pub"
+	b.Keys = []KeyFile{{Name: "1_ed25519", Data: []byte("private")}, {Name: "1_ed25519.pub", Data: []byte("public")}}
+
+	// A different local key of the same name is kept
+	if err := os.MkdirAll(sshmgr.KeysDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sshmgr.KeysDir(), "1_ed25519"), []byte("mine"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := Apply(b, Merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var web db.LocalVM
+	db.DB.Where("provider_id = 1").First(&web)
+	if filepath.Dir(web.SSHKeyPath) != sshmgr.KeysDir() || filepath.Base(web.SSHKeyPath) == "1_ed25519.pub" {
+		t.Fatalf("key path %s, want a renamed key under %s", web.SSHKeyPath, sshmgr.KeysDir())
+	}
+	if data, _ := os.ReadFile(sshmgr.PrivateKeyPath(web.SSHKeyPath)); string(data) != "private" {
+		t.Errorf("restored key holds %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(sshmgr.KeysDir(), "1_ed25519")); string(data) != "mine" {
+		t.Error("the local key was overwritten")
+	}
+	if rep.Keys != 2 || len(rep.RenamedKeys) != 1 {
+		t.Errorf("report %+v", rep)
+	}
+}
+
+func TestApplyRestoresIdentity(t *testing.T) {
+	id := &Identity{EVMAddress: "0xabc", XMRAddress: "4xmr", XMRRPC: "http://127.0.0.1:18082/json_rpc"}
+	tests := []struct {
+		name         string
+		key          bool
+		restored     []string
+		unlinkedEVM  string
+		wantEVMEntry bool
+	}{
+		{"no EVM key", false, []string{"xmr-addr", "xmr-rpc"}, "0xabc", false},
+		{"EVM key present", true, []string{"addr", "xmr-addr", "xmr-rpc"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry(t)
+			keyring.MockInit()
+			if tt.key {
+				keyring.Set(config.KeyringService, config.UserAccount+"-key", "deadbeef")
+			}
+			b := incoming()
+			b.Identity = id
+
+			rep, err := Apply(b, Merge)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equal(rep.Identity, tt.restored) || rep.UnlinkedEVM != tt.unlinkedEVM {
+				t.Errorf("restored %v, unlinked EVM %q", rep.Identity, rep.UnlinkedEVM)
+			}
+			_, err = keyring.Get(config.KeyringService, config.UserAccount+"-addr")
+			if got := err == nil; got != tt.wantEVMEntry {
+				t.Errorf("EVM address in keyring = %v, want %v", got, tt.wantEVMEntry)
+			}
+		})
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := incoming()
+	b.Keys = []KeyFile{{Name: "k", Data: []byte{0, 1, 2}}}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf, id.Recipient()); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("203.0.113.1")) {
+		t.Fatal("bundle is not encrypted")
+	}
+
+	got, err := Read(bytes.NewReader(buf.Bytes()), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.VMs) != 3 || len(got.Grants) != 2 || len(got.Payments) != 3 || got.VMs[0].Alias != "web" || !bytes.Equal(got.Keys[0].Data, []byte{0, 1, 2}) {
+		t.Errorf("round trip lost data: %+v", got)
+	}
+
+	other, _ := age.GenerateX25519Identity()
+	if _, err := Read(bytes.NewReader(buf.Bytes()), other); err == nil {
+		t.Error("read with the wrong identity succeeded")
+	}
+}
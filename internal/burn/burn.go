@@ -0,0 +1,263 @@
+// Package burn tears down every VM of the active identities and then erases
+// everything entropy has stored on this machine.
+package burn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/creds"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/eventlog"
+	"github.com/x402-Systems/entropy/internal/fleet"
+	"github.com/x402-Systems/entropy/internal/shred"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/zalando/go-keyring"
+)
+
+// pollEvery spaces the /list calls that confirm the teardown; each is paid
+var pollEvery = 10 * time.Second
+
+// keyringSuffixes are every account entropy stores under config.UserAccount
+var keyringSuffixes = []string{"-key", "-addr", "-xmr-addr", "-xmr-rpc", "-db-key"}
+
+// ErrIncomplete means at least one remote VM may still be running
+var ErrIncomplete = errors.New("remote teardown incomplete")
+
+// Failure is one thing burn could not destroy or remove
+type Failure struct {
+	Item  string `json:"item"`
+	Error string `json:"error"`
+}
+
+// Report is what burn did
+type Report struct {
+	Identities []string `json:"identities"`
+	Destroyed  []string `json:"destroyed"`
+	Confirmed  bool     `json:"confirmed"`
+	// Orphans are remote VMs with no local record; without a name they cannot be addressed
+	Orphans []int64   `json:"orphans,omitempty"`
+	Removed []string  `json:"removed"`
+	Failed  []Failure `json:"failed"`
+}
+
+// Step is the phase a Teardown progress line belongs to
+type Step int
+
+const (
+	Destroying Step = iota // a destroy request is about to be sent
+	Confirming             // waiting for /list to drop the destroyed VMs
+)
+
+func (r *Report) fail(item string, err error) {
+	r.Failed = append(r.Failed, Failure{Item: item, Error: err.Error()})
+}
+
+// clients returns one client per active identity: the EVM address and, when
+// a Monero wallet is linked as well, its derived ID
+func clients(client *api.Client) map[string]*api.Client {
+	out := map[string]*api.Client{client.PayerID: client}
+	if addr, err := keyring.Get(config.KeyringService, config.UserAccount+"-xmr-addr"); err == nil {
+		if id := api.DeriveMoneroID(addr); id != client.PayerID {
+			c := *client
+			c.PayerID = id
+			out[id] = &c
+		}
+	}
+	return out
+}
+
+// Teardown destroys every VM the orchestrator lists for the active identities
+// and polls /list until they are gone or wait expires. notify receives
+// plain progress lines. ErrIncomplete is returned if anything may still be running.
+func Teardown(ctx context.Context, client *api.Client, wait time.Duration, notify func(Step, string)) (*Report, error) {
+	rep := &Report{}
+	incomplete := false
+	pending := make(map[string]map[int64]db.LocalVM) // payer -> ProviderID -> node
+
+	cs := clients(client)
+	for payer, c := range cs {
+		rep.Identities = append(rep.Identities, payer)
+		list, err := c.List(ctx)
+		if err != nil {
+			rep.fail("list "+payer, err)
+			incomplete = true
+			continue
+		}
+
+		for _, r := range list.VMs {
+			var vm db.LocalVM
+			if db.DB.Where("provider_id = ?", r.ProviderID).Limit(1).Find(&vm).RowsAffected == 0 {
+				rep.Orphans = append(rep.Orphans, r.ProviderID)
+				incomplete = true
+				continue
+			}
+			notify(Destroying, fmt.Sprintf("Destroying %s (%s)...", vm.Alias, vm.ServerName))
+			// The registry row stays until /list confirms, so a retry can still address the VM
+			if err := c.Destroy(ctx, vm.ServerName); err != nil {
+				slog.Error("destroy failed", "alias", vm.Alias, "name", vm.ServerName, "err", err)
+				rep.fail(vm.Alias, err)
+				incomplete = true
+				continue
+			}
+			rep.Destroyed = append(rep.Destroyed, vm.Alias)
+			if pending[payer] == nil {
+				pending[payer] = make(map[int64]db.LocalVM)
+			}
+			pending[payer][r.ProviderID] = vm
+		}
+	}
+
+	if len(rep.Destroyed) > 0 {
+		notify(Confirming, fmt.Sprintf("Waiting up to %s for the orchestrator to confirm...", wait))
+	}
+	deadline := time.Now().Add(wait)
+	for len(pending) > 0 && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return rep, ctx.Err()
+		case <-time.After(pollEvery):
+		}
+		for payer, ids := range pending {
+			list, err := cs[payer].List(ctx)
+			if err != nil {
+				continue
+			}
+			listed := make(map[int64]bool, len(list.VMs))
+			for _, r := range list.VMs {
+				listed[r.ProviderID] = true
+			}
+			for id, vm := range ids {
+				if listed[id] {
+					continue
+				}
+				delete(ids, id)
+				if err := fleet.Forget(vm); err != nil {
+					rep.fail("local record of "+vm.Alias, err)
+				}
+			}
+			if len(ids) == 0 {
+				delete(pending, payer)
+			}
+		}
+	}
+	for _, ids := range pending {
+		for _, vm := range ids {
+			rep.fail(vm.Alias, fmt.Errorf("still listed by the orchestrator after %s", wait))
+			incomplete = true
+		}
+	}
+
+	rep.Confirmed = !incomplete
+	slog.Info("burn teardown finished", "destroyed", len(rep.Destroyed), "orphans", len(rep.Orphans), "confirmed", rep.Confirmed)
+	if incomplete {
+		return rep, ErrIncomplete
+	}
+	return rep, nil
+}
+
+// Wipe erases the registry and its backups, logs, SSH keys, cached manifests,
+// lock files, the managed ssh_config, stored root passwords and every keyring
+// entry. Files are overwritten with random data before removal. Only the
+// settings files are kept; everything that resists is added to rep.Failed.
+func Wipe(rep *Report) {
+	dir := config.Dir()
+
+	// Keys leave the agent while their public halves still exist
+	filepath.WalkDir(sshmgr.KeysDir(), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() && filepath.Ext(path) != ".pub" {
+			sshmgr.RemoveFromAgent(path)
+		}
+		return nil
+	})
+
+	// Root passwords are keyed by ProviderID, so they go while the registry can still list them
+	var vms []db.LocalVM
+	db.DB.Find(&vms)
+	for _, vm := range vms {
+		if err := creds.Delete(vm.ProviderID); err != nil {
+			rep.fail("root password of "+vm.Alias, err)
+		}
+	}
+
+	if err := db.Close(); err != nil {
+		rep.fail("registry", err)
+	}
+	for _, f := range db.Files() {
+		rep.shred(f)
+	}
+
+	eventlog.Stop()
+	for _, sub := range []string{"logs", "keys", "cache", "locks"} {
+		rep.shredDir(filepath.Join(dir, sub))
+	}
+	for _, f := range []string{sshmgr.ConfigPath(), sshmgr.ConfigPath() + ".tmp"} {
+		if _, err := os.Stat(f); err == nil {
+			rep.shred(f)
+		}
+	}
+	if removed, err := sshmgr.RemoveInclude(); err != nil {
+		rep.fail("Include line in ~/.ssh/config", err)
+	} else if removed {
+		rep.Removed = append(rep.Removed, "Include line in ~/.ssh/config")
+	}
+
+	for _, suffix := range keyringSuffixes {
+		account := config.UserAccount + suffix
+		err := keyring.Delete(config.KeyringService, account)
+		switch {
+		case err == nil:
+			rep.Removed = append(rep.Removed, "keyring "+config.KeyringService+"/"+account)
+		case !errors.Is(err, keyring.ErrNotFound):
+			rep.fail("keyring "+config.KeyringService+"/"+account, err)
+		}
+	}
+
+	// Settings are preferences rather than state and stay behind; the directory
+	// goes unless they are all that is left
+	entries, _ := os.ReadDir(dir)
+	onlySettings := len(entries) > 0
+	for _, e := range entries {
+		if p := filepath.Join(dir, e.Name()); p != config.SettingsPath() && p != config.KeymapPath() {
+			onlySettings = false
+		}
+	}
+	if !onlySettings {
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			rep.fail(dir, err)
+		}
+	}
+}
+
+func (r *Report) shredDir(dir string) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return
+	}
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			r.shred(path)
+		}
+		return nil
+	})
+	if err := os.RemoveAll(dir); err != nil {
+		r.fail(dir, err)
+	}
+}
+
+// shred overwrites and removes path; see shred.File
+func (r *Report) shred(path string) {
+	if err := shred.File(path); err != nil {
+		r.fail(path, err)
+		return
+	}
+	r.Removed = append(r.Removed, path)
+}
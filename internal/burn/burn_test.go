@@ -0,0 +1,174 @@
+package burn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/flock"
+
+	"github.com/zalando/go-keyring"
+)
+
+// registry opens a fresh registry under a temp HOME with a mock keyring
+func registry(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+	keyring.MockInit()
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+}
+
+// orchestrator fakes /list and DELETE /provision. Destroying a name in fail
+// is refused; a name in sticky is accepted but stays listed.
+type orchestrator struct {
+	mu     sync.Mutex
+	live   map[string]int64 // server name -> ProviderID
+	fail   map[string]bool
+	sticky map[string]bool
+}
+
+func (o *orchestrator) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	rec := httptest.NewRecorder()
+	switch {
+	case req.Method == "GET" && req.URL.Path == "/list":
+		list := api.ListResponse{}
+		for _, id := range o.live {
+			list.VMs = append(list.VMs, api.RemoteVM{ProviderID: id})
+		}
+		json.NewEncoder(rec).Encode(list)
+	case req.Method == "DELETE" && req.URL.Path == "/provision":
+		name := req.Header.Get("X-VM-NAME")
+		if o.fail[name] {
+			http.Error(rec, "refused", http.StatusInternalServerError)
+			break
+		}
+		if !o.sticky[name] {
+			delete(o.live, name)
+		}
+	default:
+		http.NotFound(rec, req)
+	}
+	return rec.Result(), nil
+}
+
+func TestTeardownForgetsOnlyConfirmedNodes(t *testing.T) {
+	registry(t)
+	old := pollEvery
+	pollEvery = 5 * time.Millisecond
+	t.Cleanup(func() { pollEvery = old })
+
+	for i, alias := range []string{"web", "db", "cache"} {
+		db.DB.Create(&db.LocalVM{ProviderID: int64(i + 1), Alias: alias, ServerName: "srv-" + alias, ExpiresAt: time.Now().Add(time.Hour)})
+	}
+	o := &orchestrator{
+		live:   map[string]int64{"srv-web": 1, "srv-db": 2, "srv-cache": 3},
+		fail:   map[string]bool{"srv-db": true},
+		sticky: map[string]bool{"srv-cache": true},
+	}
+	client := &api.Client{HTTPClient: &http.Client{Transport: o}, PayerID: "0xabc"}
+
+	var lines []string
+	steps := map[Step]int{}
+	rep, err := Teardown(context.Background(), client, 50*time.Millisecond, func(step Step, s string) {
+		steps[step]++
+		lines = append(lines, s)
+	})
+	if !errors.Is(err, ErrIncomplete) || rep.Confirmed {
+		t.Fatalf("Teardown = %v, confirmed %v; want ErrIncomplete", err, rep.Confirmed)
+	}
+
+	var left []db.LocalVM
+	db.DB.Order("provider_id").Find(&left)
+	if len(left) != 2 || left[0].Alias != "db" || left[1].Alias != "cache" {
+		t.Errorf("registry holds %+v, want the refused and the unconfirmed node", left)
+	}
+
+	// The command adds the themed icons
+	if steps[Destroying] != 3 || steps[Confirming] != 1 {
+		t.Errorf("progress steps %v, want 3 destroying and 1 confirming", steps)
+	}
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "Destroying ") && !strings.HasPrefix(l, "Waiting ") {
+			t.Errorf("progress line %q is not plain text", l)
+		}
+	}
+
+	failed := map[string]string{}
+	for _, f := range rep.Failed {
+		failed[f.Item] = f.Error
+	}
+	if !strings.Contains(failed["db"], "refused") || !strings.Contains(failed["cache"], "still listed") || len(failed) != 2 {
+		t.Errorf("failures %+v", rep.Failed)
+	}
+}
+
+func TestWipe(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   []string
+		wantDir bool
+		// wantFail is whether removing the config dir is reported
+		wantFail bool
+	}{
+		{"everything goes", nil, false, false},
+		{"settings stay", []string{"config.json", "keys.json"}, true, false},
+		{"unknown file is reported", []string{"stray"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry(t)
+			dir := config.Dir()
+			db.DB.Create(&db.LocalVM{ProviderID: 1, Alias: "web"})
+			for _, f := range append([]string{"keys/1_ed25519", "cache/options.json", "logs/entropy.log"}, tt.extra...) {
+				path := filepath.Join(dir, f)
+				os.MkdirAll(filepath.Dir(path), 0700)
+				if err := os.WriteFile(path, []byte("secret"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			l, err := flock.TryLock("renew-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Unlock()
+
+			rep := &Report{}
+			Wipe(rep)
+
+			for _, sub := range []string{"keys", "cache", "logs", "locks", "entropy.db"} {
+				if _, err := os.Stat(filepath.Join(dir, sub)); !os.IsNotExist(err) {
+					t.Errorf("%s survived the wipe", sub)
+				}
+			}
+			if _, err := os.Stat(dir); (err == nil) != tt.wantDir {
+				t.Errorf("config dir exists = %v, want %v", err == nil, tt.wantDir)
+			}
+			want := 0
+			if tt.wantFail {
+				want = 1
+			}
+			if len(rep.Failed) != want || want == 1 && rep.Failed[0].Item != dir {
+				t.Errorf("failures %+v, want the config dir reported = %v", rep.Failed, tt.wantFail)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+)
+
 var Version = "v1.3.0"
 
 const (
@@ -10,3 +15,9 @@ const (
 
 	DefaultMoneroRPC = "http://127.0.0.1:18084/json_rpc"
 )
+
+// Dir returns the local state directory (~/.config/entropy)
+func Dir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "entropy")
+}
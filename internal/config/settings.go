@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Settings are user preferences read from ~/.config/entropy/config.json.
+// Missing fields fall back to the defaults below.
+type Settings struct {
+	// SyncInterval is how often the TUI pays for a /list refresh, e.g. "30s" or "manual"
+	SyncInterval string `json:"sync_interval"`
+	// Theme is one of default, light, high-contrast or mono. NO_COLOR forces mono.
+	Theme string `json:"theme"`
+	// EVMRPC is the JSON-RPC endpoint used to read the USDC balance
+	EVMRPC string `json:"evm_rpc"`
+	// USDCContract is the ERC-20 token queried with eth_call balanceOf
+	USDCContract string `json:"usdc_contract"`
+}
+
+const (
+	DefaultSyncInterval = 30 * time.Second
+	DefaultEVMRPC       = "https://mainnet.base.org"
+	// DefaultUSDCContract is native USDC on Base mainnet
+	DefaultUSDCContract = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+)
+
+func SettingsPath() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+// LoadSettings never fails: an unreadable or malformed file yields the defaults
+func LoadSettings() Settings {
+	s := Settings{
+		SyncInterval: DefaultSyncInterval.String(),
+		EVMRPC:       DefaultEVMRPC,
+		USDCContract: DefaultUSDCContract,
+	}
+
+	data, err := os.ReadFile(SettingsPath())
+	if err != nil {
+		return s
+	}
+	json.Unmarshal(data, &s)
+	return s
+}
+
+// KeymapPath holds optional TUI key overrides: {"renew": ["r", "R"], ...}
+func KeymapPath() string {
+	return filepath.Join(Dir(), "keys.json")
+}
+
+// LoadKeymap reads the key overrides. A missing file is not an error.
+func LoadKeymap() (map[string][]string, error) {
+	data, err := os.ReadFile(KeymapPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string][]string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%s: %w", KeymapPath(), err)
+	}
+	return keys, nil
+}
+
+// ParseSyncInterval turns a sync interval setting into a duration.
+// Zero means manual-only; values below 10s are raised to 10s.
+func ParseSyncInterval(v string) time.Duration {
+	v = strings.TrimSpace(strings.ToLower(v))
+	switch v {
+	case "", "default":
+		return DefaultSyncInterval
+	case "manual", "off", "0":
+		return 0
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return DefaultSyncInterval
+	}
+	if d <= 0 {
+		return 0
+	}
+	if d < 10*time.Second {
+		d = 10 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSyncInterval(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":        DefaultSyncInterval,
+		"default": DefaultSyncInterval,
+		"bogus":   DefaultSyncInterval,
+		"manual":  0,
+		" OFF ":   0,
+		"0":       0,
+		"-5m":     0,
+		"2s":      10 * time.Second,
+		"10s":     10 * time.Second,
+		"2m":      2 * time.Minute,
+		"1H":      time.Hour,
+	}
+	for in, want := range tests {
+		if got := ParseSyncInterval(in); got != want {
+			t.Errorf("ParseSyncInterval(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
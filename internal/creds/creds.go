@@ -0,0 +1,37 @@
+// Package creds keeps per-VM root passwords in the OS keyring, keyed by
+// ProviderID so they survive alias changes.
+package creds
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/x402-Systems/entropy/internal/config"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrNotFound means no password is stored for the node
+var ErrNotFound = keyring.ErrNotFound
+
+func account(providerID int64) string {
+	return config.UserAccount + "-root-" + strconv.FormatInt(providerID, 10)
+}
+
+// Store saves the root password of a node
+func Store(providerID int64, password string) error {
+	return keyring.Set(config.KeyringService, account(providerID), password)
+}
+
+// Get returns the stored root password of a node
+func Get(providerID int64) (string, error) {
+	return keyring.Get(config.KeyringService, account(providerID))
+}
+
+// Delete removes the root password of a node. A node without one is not an error.
+func Delete(providerID int64) error {
+	if err := keyring.Delete(config.KeyringService, account(providerID)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const writesPerWorker = 25
+
+// write records one grant inside a transaction and one outside, the two
+// ways commands touch the registry
+func write(worker, i int) error {
+	err := Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Model(&AccessGrant{}).Where("provider_id = ?", worker).Count(&n).Error; err != nil {
+			return err
+		}
+		return tx.Create(&AccessGrant{ProviderID: int64(worker), Grantee: "tx", Fingerprint: fmt.Sprintf("tx-%d-%d", worker, i)}).Error
+	})
+	if err != nil {
+		return err
+	}
+	return DB.Create(&AccessGrant{ProviderID: int64(worker), Grantee: "direct", Fingerprint: fmt.Sprintf("direct-%d-%d", worker, i)}).Error
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func grantCount(t *testing.T) int64 {
+	t.Helper()
+	var n int64
+	if err := DB.Model(&AccessGrant{}).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestConcurrentTransactions(t *testing.T) {
+	tempRegistry(t)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*writesPerWorker)
+	for w := 1; w <= workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writesPerWorker; i++ {
+				if err := write(w, i); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := grantCount(t); n != workers*writesPerWorker*2 {
+		t.Errorf("registry has %d grants, want %d", n, workers*writesPerWorker*2)
+	}
+}
+
+// TestHelperWriter is not a test: TestConcurrentProcesses runs the test
+// binary again with ENTROPY_DB_WRITER set to the worker number, sharing the
+// parent's HOME. Any error goes to stderr and fails the process.
+func TestHelperWriter(t *testing.T) {
+	worker, err := strconv.Atoi(os.Getenv("ENTROPY_DB_WRITER"))
+	if err != nil {
+		return
+	}
+	if err := Open(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// Opening an encrypted registry takes a while; start writing together
+	os.WriteFile(filepath.Join(os.Getenv("HOME"), fmt.Sprintf("ready-%d", worker)), nil, 0600)
+	for !exists(filepath.Join(os.Getenv("HOME"), "go")) {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < writesPerWorker; i++ {
+		if err := write(worker, i); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	Close()
+	os.Exit(0)
+}
+
+func TestConcurrentProcesses(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{"plaintext", func(t *testing.T) {
+			tempRegistry(t)
+			if err := Init(); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"encrypted", encryptedRegistry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			const workers = 4
+			cmds := make([]*exec.Cmd, workers)
+			outs := make([]bytes.Buffer, workers)
+			for w := range cmds {
+				cmds[w] = exec.Command(os.Args[0], "-test.run=^TestHelperWriter$")
+				cmds[w].Env = append(os.Environ(), fmt.Sprintf("ENTROPY_DB_WRITER=%d", w+1))
+				cmds[w].Stdout, cmds[w].Stderr = &outs[w], &outs[w]
+				if err := cmds[w].Start(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			deadline := time.Now().Add(30 * time.Second)
+			for w := range cmds {
+				for !exists(filepath.Join(os.Getenv("HOME"), fmt.Sprintf("ready-%d", w+1))) && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+			}
+			os.WriteFile(filepath.Join(os.Getenv("HOME"), "go"), nil, 0600)
+
+			for w, cmd := range cmds {
+				err := cmd.Wait()
+				switch out := outs[w].String(); {
+				case strings.Contains(out, "database is locked"):
+					t.Errorf("writer %d hit SQLITE_BUSY: %s", w+1, out)
+				case err != nil:
+					t.Errorf("writer %d failed: %v: %s", w+1, err, out)
+				}
+			}
+			// Every write of every process survives, none overwrote another's
+			if n := grantCount(t); n != workers*writesPerWorker*2 {
+				t.Errorf("registry has %d grants, want %d", n, workers*writesPerWorker*2)
+			}
+		})
+	}
+}
@@ -4,26 +4,92 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/x402-Systems/entropy/internal/config"
+
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
+// dsnOptions let the TUI, one-off commands and background renewers share the
+// file: WAL keeps readers and the writer from blocking each other, a writer
+// waits up to 10s for another instead of failing with "database is locked",
+// and transactions take the write lock at BEGIN so two of them can never
+// deadlock upgrading from a read lock.
+const dsnOptions = "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+
+// Path is the registry file, ~/.config/entropy/entropy.db
+func Path() string {
+	return filepath.Join(config.Dir(), "entropy.db")
+}
+
+// Init opens the registry and applies any pending migrations
 func Init() error {
-	home, _ := os.UserHomeDir()
-	configDir := filepath.Join(home, ".config", "entropy")
+	if err := Open(); err != nil {
+		return err
+	}
+	_, err := Migrate()
+	return err
+}
 
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+// Open opens the registry as it is on disk, without migrating it
+func Open() error {
+	if err := os.MkdirAll(config.Dir(), 0700); err != nil {
 		return err
 	}
+	tightenPermissions()
+
+	if Encrypted() {
+		return openEncrypted()
+	}
 
-	dbPath := filepath.Join(configDir, "entropy.db")
 	var err error
-	DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	DB, err = gorm.Open(sqlite.Open(Path()+dsnOptions), &gorm.Config{})
 	if err != nil {
 		return err
 	}
+	os.Chmod(Path(), 0600)
+	return nil
+}
+
+// tightenPermissions restricts state written by older releases, which created
+// the directory 0755 and the registry with the process umask
+func tightenPermissions() {
+	dir := config.Dir()
+	os.Chmod(dir, 0700)
+	for _, sub := range []string{"keys", "logs", "cache"} {
+		os.Chmod(filepath.Join(dir, sub), 0700)
+	}
+
+	for _, f := range Files() {
+		if info, err := os.Stat(f); err == nil && info.Mode().Perm()&0077 != 0 {
+			os.Chmod(f, 0600)
+		}
+	}
+}
 
-	return DB.AutoMigrate(&LocalVM{})
+// Files lists every registry file present on disk: the database and its
+// journals, the encrypted copy and pre-migration backups
+func Files() []string {
+	var files []string
+	for _, f := range []string{Path(), Path() + "-wal", Path() + "-shm", Path() + "-journal", EncryptedPath(), EncryptedPath() + ".tmp"} {
+		if _, err := os.Stat(f); err == nil {
+			files = append(files, f)
+		}
+	}
+	backups, _ := filepath.Glob(filepath.Join(config.Dir(), "entropy-*.bak"))
+	return append(files, backups...)
+}
+
+// Close releases the registry. DB must not be used afterwards.
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
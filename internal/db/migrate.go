@@ -0,0 +1,197 @@
+package db
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one ordered schema change. Up runs inside a transaction
+// together with the schema_version insert, so a failure leaves no trace.
+//
+// Migrations describe tables with their own frozen structs rather than the
+// live models: a model may change later, a migration never does.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+}
+
+// SchemaVersion records each applied migration
+type SchemaVersion struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaVersion) TableName() string { return "schema_version" }
+
+// MigrationState is a migration as reported by `entropy db migrate --status`
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// v1LocalVM is local_vms as it stood before versioned migrations. AutoMigrate
+// only adds what is missing, so older registries are brought up to it too.
+type v1LocalVM struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProviderID  int64  `gorm:"uniqueIndex"`
+	Alias       string `gorm:"uniqueIndex"`
+	ServerName  string
+	IP          string
+	Region      string
+	Tier        string
+	SSHKeyPath  string
+	OwnerWallet string    `gorm:"index"`
+	ExpiresAt   time.Time `gorm:"index"`
+	CreatedAt   time.Time
+}
+
+func (v1LocalVM) TableName() string { return "local_vms" }
+
+type v2AccessGrant struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProviderID  int64  `gorm:"index"`
+	Grantee     string `gorm:"index"`
+	Fingerprint string `gorm:"index"`
+	PublicKey   string
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func (v2AccessGrant) TableName() string { return "access_grants" }
+
+type v3Payment struct {
+	ID         uint  `gorm:"primaryKey"`
+	ProviderID int64 `gorm:"index"`
+	Kind       string
+	Duration   string
+	Network    string
+	Asset      string
+	Amount     string
+	Value      float64
+	Unit       string
+	TxHash     string
+	PaidAt     time.Time
+}
+
+func (v3Payment) TableName() string { return "payments" }
+
+// migrations must stay in Version order; append only
+var migrations = []Migration{
+	{1, "local_vms baseline", func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&v1LocalVM{})
+	}},
+	{2, "access_grants", func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&v2AccessGrant{})
+	}},
+	{3, "payments", func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&v3Payment{})
+	}},
+}
+
+func currentVersion() (int, error) {
+	var v int
+	err := DB.Model(&SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&v).Error
+	return v, err
+}
+
+// Migrate applies pending migrations in order. A registry that already has
+// data is copied to a timestamped backup first; its path is returned.
+func Migrate() (string, error) {
+	if err := DB.AutoMigrate(&SchemaVersion{}); err != nil {
+		return "", err
+	}
+	current, err := currentVersion()
+	if err != nil {
+		return "", err
+	}
+
+	if latest := migrations[len(migrations)-1].Version; current > latest {
+		slog.Warn("registry schema is newer than this build", "version", current, "known", latest)
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > current {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return "", nil
+	}
+
+	backup := ""
+	if DB.Migrator().HasTable("local_vms") {
+		stamp := time.Now().Format("20060102-150405")
+		if store != nil {
+			// The sealed file is the backup; plaintext never leaves memory
+			backup = filepath.Join(filepath.Dir(Path()), fmt.Sprintf("entropy-%s.db.enc.bak", stamp))
+			err = copyFile(EncryptedPath(), backup)
+		} else {
+			backup = filepath.Join(filepath.Dir(Path()), fmt.Sprintf("entropy-%s.db.bak", stamp))
+			// VACUUM INTO writes a consistent copy even with open connections
+			if err = DB.Exec("VACUUM INTO ?", backup).Error; err == nil {
+				err = os.Chmod(backup, 0600)
+			}
+		}
+		if err != nil {
+			return "", fmt.Errorf("backup before migrating failed: %w", err)
+		}
+		slog.Info("registry backed up before migrating", "path", backup, "from_version", current)
+	}
+
+	for _, m := range pending {
+		err := Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			slog.Error("migration failed", "version", m.Version, "name", m.Name, "err", err)
+			return backup, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		slog.Info("migration applied", "version", m.Version, "name", m.Name)
+	}
+	return backup, nil
+}
+
+// MigrationStatus lists every known migration and when it was applied
+func MigrationStatus() ([]MigrationState, error) {
+	var applied []SchemaVersion
+	// Registries from before versioning have no schema_version table yet
+	if DB.Migrator().HasTable(&SchemaVersion{}) {
+		if err := DB.Order("version").Find(&applied).Error; err != nil {
+			return nil, err
+		}
+	}
+	at := make(map[int]time.Time, len(applied))
+	for _, a := range applied {
+		at[a.Version] = a.AppliedAt
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		s := MigrationState{Version: m.Version, Name: m.Name}
+		if t, ok := at[m.Version]; ok {
+			s.AppliedAt = &t
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0600)
+}
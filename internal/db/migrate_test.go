@@ -0,0 +1,203 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// tempRegistry points the registry at a fresh temp dir and closes whatever the
+// test opened
+func tempRegistry(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("ENTROPY_DB_PASSPHRASE", "")
+	t.Cleanup(func() {
+		Close()
+		DB, store = nil, nil
+	})
+	return filepath.Join(home, ".config", "entropy")
+}
+
+// writeLegacy creates entropy.db the way releases before versioned migrations
+// did, with nothing but local_vms and the given DDL
+func writeLegacy(t *testing.T, dir, ddl string, rows ...string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := gorm.Open(sqlite.Open(filepath.Join(dir, "entropy.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range append([]string{ddl}, rows...) {
+		if err := legacy.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	sqlDB, _ := legacy.DB()
+	sqlDB.Close()
+}
+
+// The exact schema AutoMigrate produced for LocalVM before migrations existed
+const legacyLocalVMs = "CREATE TABLE `local_vms` (`id` integer PRIMARY KEY AUTOINCREMENT,`provider_id` integer,`alias` text,`server_name` text,`ip` text,`region` text,`tier` text,`ssh_key_path` text,`owner_wallet` text,`expires_at` datetime,`created_at` datetime)"
+
+// An older local_vms, from before per-node keys and owner wallets
+const olderLocalVMs = "CREATE TABLE `local_vms` (`id` integer PRIMARY KEY AUTOINCREMENT,`provider_id` integer,`alias` text,`server_name` text,`ip` text,`region` text,`tier` text,`expires_at` datetime,`created_at` datetime)"
+
+var wantColumns = map[string][]string{
+	"local_vms":      {"id", "provider_id", "alias", "server_name", "ip", "region", "tier", "ssh_key_path", "owner_wallet", "expires_at", "created_at"},
+	"access_grants":  {"id", "provider_id", "grantee", "fingerprint", "public_key", "expires_at", "created_at"},
+	"payments":       {"id", "provider_id", "kind", "duration", "network", "asset", "amount", "value", "unit", "tx_hash", "paid_at"},
+	"schema_version": {"version", "name", "applied_at"},
+}
+
+func assertSchema(t *testing.T) {
+	t.Helper()
+	var versions []SchemaVersion
+	if err := DB.Order("version").Find(&versions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != len(migrations) {
+		t.Fatalf("schema_version has %d rows, want %d", len(versions), len(migrations))
+	}
+	for i, v := range versions {
+		if v.Version != migrations[i].Version || v.Name != migrations[i].Name || v.AppliedAt.IsZero() {
+			t.Errorf("schema_version row %d = %+v, want version %d %q", i, v, migrations[i].Version, migrations[i].Name)
+		}
+	}
+
+	for table, columns := range wantColumns {
+		types, err := DB.Migrator().ColumnTypes(table)
+		if err != nil {
+			t.Fatalf("%s: %v", table, err)
+		}
+		have := make(map[string]bool, len(types))
+		for _, c := range types {
+			have[c.Name()] = true
+		}
+		for _, c := range columns {
+			if !have[c] {
+				t.Errorf("%s has no column %s", table, c)
+			}
+		}
+	}
+}
+
+func backups(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "entropy-*.db.bak"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestMigrateFreshRegistry(t *testing.T) {
+	dir := tempRegistry(t)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t)
+	if b := backups(t, dir); len(b) != 0 {
+		t.Errorf("fresh registry was backed up: %v", b)
+	}
+}
+
+func TestMigrateLegacyRegistry(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		row  string
+	}{
+		{"autoMigrate baseline", legacyLocalVMs,
+			"INSERT INTO local_vms (provider_id, alias, server_name, ip, region, tier, ssh_key_path, owner_wallet, expires_at, created_at) VALUES (42, 'web', 'srv-42', '203.0.113.7', 'fsn1', 'standard', '/k.pub', '0xabc', '2030-01-01 00:00:00', '2029-12-01 00:00:00')"},
+		{"missing columns", olderLocalVMs,
+			"INSERT INTO local_vms (provider_id, alias, server_name, ip, region, tier, expires_at, created_at) VALUES (42, 'web', 'srv-42', '203.0.113.7', 'fsn1', 'standard', '2030-01-01 00:00:00', '2029-12-01 00:00:00')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tempRegistry(t)
+			writeLegacy(t, dir, tt.ddl, tt.row)
+
+			if err := Init(); err != nil {
+				t.Fatal(err)
+			}
+			assertSchema(t)
+
+			var vm LocalVM
+			if err := DB.Where("provider_id = ?", 42).First(&vm).Error; err != nil {
+				t.Fatalf("existing row lost: %v", err)
+			}
+			if vm.Alias != "web" || vm.IP != "203.0.113.7" || !vm.ExpiresAt.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("existing row changed: %+v", vm)
+			}
+
+			b := backups(t, dir)
+			if len(b) != 1 {
+				t.Fatalf("want exactly one backup, got %v", b)
+			}
+			bak, err := gorm.Open(sqlite.Open(b[0]), &gorm.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { s, _ := bak.DB(); s.Close() }()
+			var rows, applied int64
+			bak.Table("local_vms").Count(&rows)
+			bak.Table("schema_version").Count(&applied)
+			if rows != 1 || applied != 0 || bak.Migrator().HasTable("access_grants") {
+				t.Errorf("backup is not the pre-migration registry: %d rows, %d migrations applied", rows, applied)
+			}
+
+			// Nothing is pending on the next start, so nothing is backed up again
+			Close()
+			if err := Init(); err != nil {
+				t.Fatal(err)
+			}
+			if b := backups(t, dir); len(b) != 1 {
+				t.Errorf("up-to-date registry was backed up again: %v", b)
+			}
+		})
+	}
+}
+
+func TestMigrationStatusBeforeMigrating(t *testing.T) {
+	dir := tempRegistry(t)
+	writeLegacy(t, dir, legacyLocalVMs)
+
+	if err := Open(); err != nil {
+		t.Fatal(err)
+	}
+	states, err := MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range states {
+		if s.AppliedAt != nil {
+			t.Errorf("migration %d reported applied before Migrate ran", s.Version)
+		}
+	}
+	if DB.Migrator().HasTable(&SchemaVersion{}) {
+		t.Error("reading the status created schema_version")
+	}
+
+	backup, err := Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup == "" {
+		t.Error("Migrate on an existing registry returned no backup path")
+	}
+	states, _ = MigrationStatus()
+	for _, s := range states {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %d still pending after Migrate", s.Version)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type LocalVM struct {
@@ -17,3 +19,53 @@ type LocalVM struct {
 	ExpiresAt   time.Time `gorm:"index"`
 	CreatedAt   time.Time
 }
+
+// BeforeSave stores times in UTC. SQLite compares them as text, so a row
+// written with another offset would sort wrong against a UTC bound.
+func (vm *LocalVM) BeforeSave(tx *gorm.DB) error {
+	vm.ExpiresAt = vm.ExpiresAt.UTC()
+	if vm.CreatedAt.IsZero() {
+		vm.CreatedAt = time.Now()
+	}
+	vm.CreatedAt = vm.CreatedAt.UTC()
+	return nil
+}
+
+// LiveVMs returns nodes whose lease has not lapsed and that have a routable IP
+func LiveVMs() ([]LocalVM, error) {
+	var vms []LocalVM
+	err := DB.Where("expires_at > ? AND ip NOT IN ?", time.Now().UTC(), []string{"", "IP-Allocating"}).
+		Order("alias").Find(&vms).Error
+	return vms, err
+}
+
+// AccessGrant records an extra authorized key installed on a node via `entropy share`
+type AccessGrant struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProviderID  int64  `gorm:"index"`
+	Grantee     string `gorm:"index"`
+	Fingerprint string `gorm:"index"`
+	PublicKey   string
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// Expired reports whether the grant's optional expiry has passed
+func (g AccessGrant) Expired() bool {
+	return g.ExpiresAt != nil && time.Now().After(*g.ExpiresAt)
+}
+
+// Payment is a settled x402 payment for a node: its provision or a renewal
+type Payment struct {
+	ID         uint   `gorm:"primaryKey"`
+	ProviderID int64  `gorm:"index"`
+	Kind       string // "provision" or "renew"
+	Duration   string // lease time bought, e.g. "24h"
+	Network    string
+	Asset      string
+	Amount     string  // atomic units of Asset
+	Value      float64 // Amount in whole Unit
+	Unit       string  // USDC or XMR
+	TxHash     string
+	PaidAt     time.Time
+}
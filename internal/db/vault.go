@@ -0,0 +1,672 @@
+package db
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/flock"
+	"github.com/x402-Systems/entropy/internal/shred"
+
+	"github.com/glebarez/sqlite"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+	"gorm.io/gorm"
+)
+
+// An encrypted registry lives only in entropy.db.enc. The working copy is an
+// in-memory database that is loaded at start, reloaded when another process
+// rewrites the file and sealed back after every change, so plaintext never
+// reaches the disk.
+
+const (
+	vaultMagic    = "ENTROPYDB\x01"
+	kdfKeyring    = 'k'
+	kdfPassphrase = 'p'
+	saltSize      = 16
+
+	// dbKeyAccount holds the hex key for keyring-protected registries
+	dbKeyAccount = config.UserAccount + "-db-key"
+)
+
+// ErrNoPassphrase is returned when a passphrase-protected registry is opened
+// without a way to ask for the passphrase
+var ErrNoPassphrase = errors.New("registry is passphrase-protected: set ENTROPY_DB_PASSPHRASE")
+
+// ErrChangedOnDisk is returned when entropy.db.enc was rewritten behind this
+// process's back, by a writer that did not take the registry lock. The change
+// is dropped and the file's content loaded instead of overwriting it.
+var ErrChangedOnDisk = errors.New("registry was changed by another process; this change was not saved, retry it")
+
+// PassphraseFunc prompts for the registry passphrase. ENTROPY_DB_PASSPHRASE
+// takes precedence.
+var PassphraseFunc func() ([]byte, error)
+
+func EncryptedPath() string {
+	return Path() + ".enc"
+}
+
+// Encrypted reports whether the registry is encrypted at rest
+func Encrypted() bool {
+	_, err := os.Stat(EncryptedPath())
+	return err == nil
+}
+
+// vault is the sealing state of an open encrypted registry
+type vault struct {
+	mu     sync.Mutex
+	kdf    byte
+	salt   []byte
+	key    []byte
+	sealed os.FileInfo // the snapshot this process last loaded or wrote
+	dirty  atomic.Bool
+}
+
+// store is nil while the registry is plaintext
+var store *vault
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// snapshot is the logical content of the registry: the schema as SQLite
+// reports it and every row of every table
+type snapshot struct {
+	Schema []string
+	Tables []tableRows
+}
+
+type tableRows struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+func newKeyringVault() (*vault, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := keyring.Set(config.KeyringService, dbKeyAccount, hex.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to store the registry key in the keyring: %w", err)
+	}
+	return &vault{kdf: kdfKeyring, salt: make([]byte, saltSize), key: key}, nil
+}
+
+func newPassphraseVault(passphrase []byte) (*vault, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &vault{kdf: kdfPassphrase, salt: salt, key: key}, nil
+}
+
+func deriveKey(passphrase, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, chacha20poly1305.KeySize)
+}
+
+func (v *vault) header() []byte {
+	return append(append([]byte(vaultMagic), v.kdf), v.salt...)
+}
+
+func (v *vault) seal(plain []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(v.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(v.header(), nonce...)
+	return aead.Seal(out, nonce, plain, v.header()), nil
+}
+
+// open decrypts a sealed file. The key is derived on first use and reused
+// while the salt is unchanged.
+func (v *vault) open(data []byte) ([]byte, error) {
+	headerLen := len(vaultMagic) + 1 + saltSize
+	if len(data) < headerLen+chacha20poly1305.NonceSizeX || !bytes.HasPrefix(data, []byte(vaultMagic)) {
+		return nil, fmt.Errorf("%s is not an entropy registry", EncryptedPath())
+	}
+	kdf, salt := data[len(vaultMagic)], data[len(vaultMagic)+1:headerLen]
+
+	if v.key == nil || v.kdf != kdf || !bytes.Equal(v.salt, salt) {
+		key, err := unlock(kdf, salt)
+		if err != nil {
+			return nil, err
+		}
+		v.kdf, v.salt, v.key = kdf, append([]byte(nil), salt...), key
+	}
+
+	aead, err := chacha20poly1305.NewX(v.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := data[headerLen : headerLen+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[headerLen+aead.NonceSize():], data[:headerLen])
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the registry (wrong key or passphrase)")
+	}
+	return plain, nil
+}
+
+func unlock(kdf byte, salt []byte) ([]byte, error) {
+	switch kdf {
+	case kdfKeyring:
+		h, err := keyring.Get(config.KeyringService, dbKeyAccount)
+		if err != nil {
+			return nil, fmt.Errorf("registry key missing from the keyring: %w", err)
+		}
+		return hex.DecodeString(h)
+	case kdfPassphrase:
+		pass := []byte(os.Getenv("ENTROPY_DB_PASSPHRASE"))
+		if len(pass) == 0 {
+			if PassphraseFunc == nil {
+				return nil, ErrNoPassphrase
+			}
+			var err error
+			if pass, err = PassphraseFunc(); err != nil {
+				return nil, err
+			}
+		}
+		return deriveKey(pass, salt)
+	}
+	return nil, fmt.Errorf("unknown registry key type %q", kdf)
+}
+
+// Method describes how the registry is protected: plaintext, keyring or passphrase
+func Method() string {
+	data := make([]byte, len(vaultMagic)+1)
+	f, err := os.Open(EncryptedPath())
+	if err != nil {
+		return "plaintext"
+	}
+	defer f.Close()
+	if _, err := f.Read(data); err == nil && data[len(vaultMagic)] == kdfPassphrase {
+		return "passphrase"
+	}
+	return "keyring"
+}
+
+func openMemory() (*gorm.DB, error) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// Every connection to :memory: is a separate database, so there is one
+	// connection and it is never recycled
+	sqlDB, err := gdb.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+	return gdb, nil
+}
+
+// openEncrypted loads entropy.db.enc into memory and installs the callbacks
+// that keep the file in step with it
+func openEncrypted() error {
+	v := &vault{}
+	l, err := flock.Acquire(lockName)
+	if err != nil {
+		return fmt.Errorf("failed to lock the registry: %w", err)
+	}
+	err = v.load()
+	l.Unlock()
+	if err != nil {
+		return err
+	}
+	store = v
+	registerVaultCallbacks()
+	return nil
+}
+
+// load reads and decrypts the snapshot into DB, replacing its content
+func (v *vault) load() error {
+	// Stat the handle that is read so info always describes data
+	f, err := os.Open(EncryptedPath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	plain, err := v.open(data)
+	if err != nil {
+		return err
+	}
+	var snap snapshot
+	if err := gob.NewDecoder(bytes.NewReader(plain)).Decode(&snap); err != nil {
+		return fmt.Errorf("corrupt registry snapshot: %w", err)
+	}
+
+	if DB == nil {
+		if DB, err = openMemory(); err != nil {
+			return err
+		}
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := restore(sqlDB, snap); err != nil {
+		return err
+	}
+	v.sealed = info
+	v.dirty.Store(false)
+	return nil
+}
+
+// lockName guards entropy.db.enc across processes
+const lockName = "registry"
+
+// lock serialises reload, write and seal between goroutines and between
+// entropy processes. The returned func releases it.
+func (v *vault) lock() (func(), error) {
+	v.mu.Lock()
+	l, err := flock.Acquire(lockName)
+	if err != nil {
+		v.mu.Unlock()
+		return nil, fmt.Errorf("failed to lock the registry: %w", err)
+	}
+	return func() {
+		l.Unlock()
+		v.mu.Unlock()
+	}, nil
+}
+
+// Persist seals the in-memory registry back to disk if it changed. Writes
+// outside Transaction persist on their own.
+func Persist() error {
+	if store == nil || !store.dirty.Load() {
+		return nil
+	}
+	unlock, err := store.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return store.persist()
+}
+
+// persist seals the registry; the caller holds the lock
+func (v *vault) persist() error {
+	if current, err := os.Stat(EncryptedPath()); err == nil && v.sealed != nil && !sameFile(current, v.sealed) {
+		if err := v.load(); err != nil {
+			return errors.Join(ErrChangedOnDisk, err)
+		}
+		return ErrChangedOnDisk
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	snap, err := dump(sqlDB)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snap); err != nil {
+		return err
+	}
+	sealed, err := v.seal(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := writeSealed(EncryptedPath(), sealed); err != nil {
+		return err
+	}
+	v.sealed, _ = os.Stat(EncryptedPath())
+	v.dirty.Store(false)
+	return nil
+}
+
+// reloadIfChanged picks up writes made by another entropy process; the
+// caller holds the lock
+func (v *vault) reloadIfChanged() error {
+	current, err := os.Stat(EncryptedPath())
+	if err != nil || v.sealed == nil || sameFile(current, v.sealed) {
+		return nil
+	}
+	if v.dirty.Load() {
+		slog.Warn("registry was changed by another process; dropping unsaved changes")
+	}
+	return v.load()
+}
+
+func sameFile(a, b os.FileInfo) bool {
+	return a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+// writeSealed replaces path atomically so a crash never leaves half a registry
+func writeSealed(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func inTransaction(tx *gorm.DB) bool {
+	_, ok := tx.Statement.ConnPool.(*sql.Tx)
+	return ok
+}
+
+// unlockKey carries a write statement's unlock func from its first callback
+// to its last
+const unlockKey = "entropy:unlock"
+
+// registerVaultCallbacks reloads before every statement and seals after every
+// write. A write holds the lock from its reload until its seal, so no other
+// process can slip a change in between. Statements inside Transaction do
+// neither; Transaction holds the lock for all of them.
+func registerVaultCallbacks() {
+	read := func(tx *gorm.DB) {
+		if inTransaction(tx) {
+			return
+		}
+		unlock, err := store.lock()
+		if err != nil {
+			tx.AddError(err)
+			return
+		}
+		defer unlock()
+		if err := store.reloadIfChanged(); err != nil {
+			tx.AddError(err)
+		}
+	}
+	before := func(tx *gorm.DB) {
+		if inTransaction(tx) {
+			return
+		}
+		unlock, err := store.lock()
+		if err != nil {
+			tx.AddError(err)
+			return
+		}
+		tx.InstanceSet(unlockKey, unlock)
+		if err := store.reloadIfChanged(); err != nil {
+			tx.AddError(err)
+		}
+	}
+	after := func(tx *gorm.DB) {
+		if inTransaction(tx) {
+			if tx.Error == nil {
+				store.dirty.Store(true) // Transaction persists after commit
+			}
+			return
+		}
+		unlock, ok := tx.InstanceGet(unlockKey)
+		if !ok {
+			return
+		}
+		defer unlock.(func())()
+		if tx.Error != nil {
+			return
+		}
+		store.dirty.Store(true)
+		if err := store.persist(); err != nil {
+			tx.AddError(fmt.Errorf("failed to seal registry: %w", err))
+		}
+	}
+
+	cb := DB.Callback()
+	cb.Query().Before("gorm:query").Register("entropy:reload", read)
+	cb.Row().Before("gorm:row").Register("entropy:reload", read)
+	cb.Raw().Before("gorm:raw").Register("entropy:reload", before)
+	cb.Raw().After("gorm:raw").Register("entropy:persist", after)
+	cb.Create().Before("gorm:begin_transaction").Register("entropy:reload", before)
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("entropy:persist", after)
+	cb.Update().Before("gorm:begin_transaction").Register("entropy:reload", before)
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("entropy:persist", after)
+	cb.Delete().Before("gorm:begin_transaction").Register("entropy:reload", before)
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("entropy:persist", after)
+}
+
+// Transaction is DB.Transaction followed by Persist. On an encrypted registry
+// it holds the lock throughout and starts from the latest sealed copy.
+func Transaction(fn func(tx *gorm.DB) error) error {
+	if store == nil {
+		return DB.Transaction(fn)
+	}
+	unlock, err := store.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := store.reloadIfChanged(); err != nil {
+		return err
+	}
+	if err := DB.Transaction(fn); err != nil {
+		// The rollback left memory as it was last sealed
+		store.dirty.Store(false)
+		return err
+	}
+	if !store.dirty.Load() {
+		return nil
+	}
+	return store.persist()
+}
+
+// dump reads the schema and all rows through database/sql so that none of
+// the gorm callbacks above fire
+func dump(sqlDB *sql.DB) (*snapshot, error) {
+	snap := &snapshot{}
+	rows, err := sqlDB.Query(`SELECT type, name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY type = 'index', rowid`)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for rows.Next() {
+		var typ, name, ddl string
+		if err := rows.Scan(&typ, &name, &ddl); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snap.Schema = append(snap.Schema, ddl)
+		if typ == "table" {
+			tables = append(tables, name)
+		}
+	}
+	rows.Close()
+
+	for _, t := range tables {
+		rows, err := sqlDB.Query(`SELECT * FROM "` + t + `"`)
+		if err != nil {
+			return nil, err
+		}
+		cols, _ := rows.Columns()
+		tr := tableRows{Name: t, Columns: cols}
+		for rows.Next() {
+			vals := make([]any, len(cols))
+			ptrs := make([]any, len(cols))
+			for i := range vals {
+				ptrs[i] = &vals[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			tr.Rows = append(tr.Rows, vals)
+		}
+		rows.Close()
+		snap.Tables = append(snap.Tables, tr)
+	}
+	return snap, nil
+}
+
+// restore replaces the database content with snap in one transaction
+func restore(sqlDB *sql.DB, snap snapshot) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return err
+	}
+	var existing []string
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		existing = append(existing, name)
+	}
+	rows.Close()
+	for _, t := range existing {
+		if _, err := tx.Exec(`DROP TABLE "` + t + `"`); err != nil {
+			return err
+		}
+	}
+
+	for _, ddl := range snap.Schema {
+		if _, err := tx.Exec(ddl); err != nil {
+			return err
+		}
+	}
+	for _, t := range snap.Tables {
+		quoted := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			quoted[i] = `"` + c + `"`
+		}
+		stmt := fmt.Sprintf(`INSERT INTO "%s" (%s) VALUES (%s)`, t.Name,
+			strings.Join(quoted, ","), strings.TrimSuffix(strings.Repeat("?,", len(t.Columns)), ","))
+		for _, row := range t.Rows {
+			if _, err := tx.Exec(stmt, row...); err != nil {
+				return fmt.Errorf("restoring %s: %w", t.Name, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// EncryptInPlace seals the open plaintext registry and removes the plaintext
+// database and its pre-migration backups, returning the files removed. The key
+// is derived from passphrase, or generated and kept in the keyring when it is
+// nil. The registry is closed afterwards.
+func EncryptInPlace(passphrase []byte) ([]string, error) {
+	if store != nil {
+		return nil, fmt.Errorf("registry is already encrypted")
+	}
+	var v *vault
+	var err error
+	if passphrase != nil {
+		v, err = newPassphraseVault(passphrase)
+	} else {
+		v, err = newKeyringVault()
+	}
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := v.lock()
+	if err != nil {
+		return nil, err
+	}
+	err = v.persist()
+	unlock()
+	if err != nil {
+		if v.kdf == kdfKeyring {
+			keyring.Delete(config.KeyringService, dbKeyAccount)
+		}
+		return nil, err
+	}
+
+	// Prove the sealed copy opens before deleting anything
+	check := &vault{kdf: v.kdf, salt: v.salt, key: v.key}
+	data, err := os.ReadFile(EncryptedPath())
+	if err == nil {
+		_, err = check.open(data)
+	}
+	if err != nil {
+		os.Remove(EncryptedPath())
+		return nil, fmt.Errorf("verification of the encrypted registry failed: %w", err)
+	}
+
+	if sqlDB, err := DB.DB(); err == nil {
+		sqlDB.Close()
+	}
+	return removePlaintext()
+}
+
+// removePlaintext shreds the plaintext registry and its backups; see
+// shred.File for how far the overwrite can be trusted
+func removePlaintext() ([]string, error) {
+	candidates := []string{Path(), Path() + "-wal", Path() + "-shm", Path() + "-journal"}
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(Path()), "entropy-*.db.bak"))
+	candidates = append(candidates, backups...)
+
+	var removed []string
+	var errs []error
+	for _, p := range candidates {
+		err := shred.File(p)
+		switch {
+		case err == nil:
+			removed = append(removed, p)
+		case !os.IsNotExist(err):
+			errs = append(errs, err)
+		}
+	}
+	return removed, errors.Join(errs...)
+}
+
+// DecryptInPlace writes the open encrypted registry back to a plaintext
+// entropy.db and removes the sealed file and its keyring key
+func DecryptInPlace() error {
+	if store == nil {
+		return fmt.Errorf("registry is not encrypted")
+	}
+	if _, err := os.Stat(Path()); err == nil {
+		return fmt.Errorf("%s already exists", Path())
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	if _, err := sqlDB.Exec("VACUUM INTO ?", Path()); err != nil {
+		return err
+	}
+	os.Chmod(Path(), 0600)
+
+	if err := os.Remove(EncryptedPath()); err != nil {
+		return err
+	}
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(Path()), "entropy-*.db.enc.bak"))
+	for _, b := range backups {
+		os.Remove(b)
+	}
+	if store.kdf == kdfKeyring {
+		keyring.Delete(config.KeyringService, dbKeyAccount)
+	}
+	store = nil
+	return nil
+}
@@ -0,0 +1,264 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	tempRegistry(t)
+	t.Setenv("ENTROPY_DB_PASSPHRASE", testPassphrase)
+
+	v, err := newPassphraseVault([]byte(testPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte("registry snapshot")
+	sealed, err := v.seal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, plain) {
+		t.Fatal("sealed data contains the plaintext")
+	}
+
+	// A fresh vault derives the key from the header's salt
+	got, err := (&vault{}).open(sealed)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("open = %q, %v", got, err)
+	}
+
+	tampered := func(i int) []byte {
+		b := bytes.Clone(sealed)
+		b[i] ^= 1
+		return b
+	}
+	tests := []struct {
+		name string
+		data []byte
+		pass string
+	}{
+		{"wrong passphrase", sealed, "hunter2"},
+		{"tampered header", tampered(len(vaultMagic) + 1), testPassphrase},
+		{"tampered body", tampered(len(sealed) - 1), testPassphrase},
+		{"not a registry", []byte("SQLite format 3\x00" + string(make([]byte, 64))), testPassphrase},
+		{"truncated", sealed[:len(vaultMagic)+4], testPassphrase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENTROPY_DB_PASSPHRASE", tt.pass)
+			if _, err := (&vault{}).open(tt.data); err == nil {
+				t.Error("open succeeded")
+			}
+		})
+	}
+}
+
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	tempRegistry(t)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	DB.Create(&LocalVM{ProviderID: 1, Alias: "web", IP: "203.0.113.1", ExpiresAt: expires})
+	DB.Create(&AccessGrant{ProviderID: 1, Grantee: "alice", Fingerprint: "SHA256:a", ExpiresAt: &expires})
+	DB.Create(&AccessGrant{ProviderID: 1, Grantee: "bob", Fingerprint: "SHA256:b"})
+	DB.Create(&Payment{ProviderID: 1, Kind: "provision", Value: 0.24, Unit: "USDC", TxHash: "0x1"})
+
+	src, _ := DB.DB()
+	snap, err := dump(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mem, err := openMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, _ := mem.DB()
+	defer dst.Close()
+	// restore replaces whatever is there
+	if _, err := dst.Exec("CREATE TABLE leftover (x)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := restore(dst, *snap); err != nil {
+		t.Fatal(err)
+	}
+	again, err := dump(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(snap, again) {
+		t.Errorf("restored registry differs:\n%+v\n%+v", snap, again)
+	}
+
+	var grants []AccessGrant
+	mem.Order("grantee").Find(&grants)
+	if len(grants) != 2 || grants[0].ExpiresAt == nil || !grants[0].ExpiresAt.Equal(expires) || grants[1].ExpiresAt != nil {
+		t.Errorf("grants after restore: %+v", grants)
+	}
+}
+
+// encryptedRegistry opens a passphrase-encrypted registry holding web
+func encryptedRegistry(t *testing.T) {
+	t.Helper()
+	tempRegistry(t)
+	t.Setenv("ENTROPY_DB_PASSPHRASE", testPassphrase)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Create(&LocalVM{ProviderID: 1, Alias: "web"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EncryptInPlace([]byte(testPassphrase)); err != nil {
+		t.Fatal(err)
+	}
+	DB = nil
+	if err := Open(); err != nil {
+		t.Fatal(err)
+	}
+	if store == nil {
+		t.Fatal("registry did not open encrypted")
+	}
+}
+
+// rewrite puts data back into entropy.db.enc the way a process that ignores
+// the lock would, with a later mtime so the change is visible
+func rewrite(t *testing.T, data []byte) {
+	t.Helper()
+	if err := writeSealed(EncryptedPath(), data); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(EncryptedPath(), later, later)
+}
+
+func vmCount(t *testing.T) int64 {
+	t.Helper()
+	var n int64
+	if err := DB.Model(&LocalVM{}).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestEncryptedRegistry(t *testing.T) {
+	encryptedRegistry(t)
+	if _, err := os.Stat(Path()); !os.IsNotExist(err) {
+		t.Error("plaintext registry left behind")
+	}
+	withWeb, _ := os.ReadFile(EncryptedPath())
+
+	if err := DB.Create(&LocalVM{ProviderID: 2, Alias: "db"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if vmCount(t) != 2 {
+		t.Fatal("write was not applied")
+	}
+	withDB, _ := os.ReadFile(EncryptedPath())
+	if bytes.Equal(withWeb, withDB) {
+		t.Fatal("write was not sealed")
+	}
+
+	// Another process's write is picked up by the next read
+	rewrite(t, withWeb)
+	if n := vmCount(t); n != 1 {
+		t.Errorf("read after a rewrite saw %d nodes, want the 1 on disk", n)
+	}
+
+	// and by Transaction before fn runs
+	rewrite(t, withDB)
+	err := Transaction(func(tx *gorm.DB) error {
+		var n int64
+		tx.Model(&LocalVM{}).Count(&n)
+		if n != 2 {
+			t.Errorf("transaction started from %d nodes, want the 2 on disk", n)
+		}
+		return tx.Create(&LocalVM{ProviderID: 3, Alias: "cache"}).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh open sees everything that was sealed
+	Close()
+	DB, store = nil, nil
+	if err := Open(); err != nil {
+		t.Fatal(err)
+	}
+	if n := vmCount(t); n != 3 {
+		t.Errorf("reopened registry has %d nodes, want 3", n)
+	}
+}
+
+func TestPersistRefusesToOverwrite(t *testing.T) {
+	encryptedRegistry(t)
+	before, _ := os.ReadFile(EncryptedPath())
+
+	// This process changes memory while another rewrites the file
+	store.mu.Lock()
+	sqlDB, _ := DB.DB()
+	if _, err := sqlDB.Exec("INSERT INTO local_vms (provider_id, alias) VALUES (2, 'db')"); err != nil {
+		t.Fatal(err)
+	}
+	store.dirty.Store(true)
+	store.mu.Unlock()
+
+	other, err := (&vault{}).open(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resealed, err := store.seal(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite(t, resealed)
+
+	if err := Persist(); !errors.Is(err, ErrChangedOnDisk) {
+		t.Fatalf("Persist = %v, want ErrChangedOnDisk", err)
+	}
+	if after, _ := os.ReadFile(EncryptedPath()); !bytes.Equal(after, resealed) {
+		t.Error("the other process's write was overwritten")
+	}
+	if n := vmCount(t); n != 1 {
+		t.Errorf("memory holds %d nodes after the conflict, want the 1 on disk", n)
+	}
+}
+
+func TestEncryptInPlaceShredsPlaintext(t *testing.T) {
+	tempRegistry(t)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Create(&LocalVM{ProviderID: 1, Alias: "secret-node-alias"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// A second link sees the blocks entropy.db pointed at after it is unlinked
+	peek := Path() + ".link"
+	if err := os.Link(Path(), peek); err != nil {
+		t.Skipf("hard links unavailable: %v", err)
+	}
+
+	removed, err := EncryptInPlace([]byte(testPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) == 0 || removed[0] != Path() {
+		t.Errorf("removed %v, want %s first", removed, Path())
+	}
+	data, err := os.ReadFile(peek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret-node-alias")) {
+		t.Error("plaintext registry was unlinked without being overwritten")
+	}
+}
@@ -0,0 +1,109 @@
+package eventlog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
+)
+
+// maxSize is the point at which the log is rotated to entropy.log.1 on startup
+const maxSize = 5 << 20
+
+// Entry is a log record as shown in the dashboard log pane
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   string
+}
+
+var recent = make(chan Entry, 256)
+
+// file is the open log, kept so Stop can release it
+var file *os.File
+
+// Path is the structured log file inside the config dir
+func Path() string {
+	return filepath.Join(config.Dir(), "logs", "entropy.log")
+}
+
+// Entries streams records logged after Init. Records are dropped, not
+// queued, while nobody is reading.
+func Entries() <-chan Entry {
+	return recent
+}
+
+// Init points slog's default logger at the JSON log file. If the file
+// cannot be opened logging is discarded so command output stays clean.
+// Note that slog.SetDefault also captures the standard log package.
+func Init() (io.Closer, error) {
+	f, err := open()
+	if err != nil {
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		return nil, err
+	}
+
+	file = f
+	json := slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelInfo})
+	slog.SetDefault(slog.New(teeHandler{Handler: json}))
+	return f, nil
+}
+
+// Stop discards all further logging and closes the log file, so it can be
+// deleted (Windows refuses to remove open files)
+func Stop() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if file != nil {
+		file.Close()
+		file = nil
+	}
+}
+
+func open() (*os.File, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxSize {
+		os.Rename(path, path+".1")
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+}
+
+// teeHandler writes to the file and also publishes each record to Entries
+type teeHandler struct {
+	slog.Handler
+	attrs []slog.Attr
+}
+
+func (h teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	parts := make([]string, 0, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		parts = append(parts, a.String())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		parts = append(parts, a.String())
+		return true
+	})
+
+	select {
+	case recent <- Entry{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: strings.Join(parts, " ")}:
+	default:
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return teeHandler{Handler: h.Handler.WithAttrs(attrs), attrs: append(slices.Clip(h.attrs), attrs...)}
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+	return teeHandler{Handler: h.Handler.WithGroup(name), attrs: h.attrs}
+}
@@ -0,0 +1,136 @@
+package fleet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/db"
+
+	"gorm.io/gorm"
+)
+
+// Cost is what has been paid for a node so far. Totals are kept per
+// settlement currency since USDC and XMR payments do not add up.
+type Cost struct {
+	Totals      map[string]float64 `json:"totals"` // unit -> whole units
+	Renewals    int                `json:"renewals"`
+	LeasedHours float64            `json:"leased_hours"`
+	// HourlyRate is the spend per hour leased with each currency
+	HourlyRate map[string]float64 `json:"hourly_rate"`
+	Payments   []db.Payment       `json:"payments,omitempty"`
+}
+
+// String is a compact total such as "0.42 USDC" or "0.1 USDC+0.002 XMR"
+func (c *Cost) String() string {
+	if c == nil || len(c.Totals) == 0 {
+		return "-"
+	}
+	return formatAmounts(c.Totals, "", "+")
+}
+
+// FormatRate renders HourlyRate, e.g. "0.0125 USDC/h"
+func (c *Cost) FormatRate() string {
+	if c == nil || len(c.HourlyRate) == 0 {
+		return "-"
+	}
+	return formatAmounts(c.HourlyRate, "/h", ", ")
+}
+
+func formatAmounts(amounts map[string]float64, suffix, sep string) string {
+	units := make([]string, 0, len(amounts))
+	for unit := range amounts {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	parts := make([]string, len(units))
+	for i, unit := range units {
+		parts[i] = fmt.Sprintf("%.4g %s%s", amounts[unit], unit, suffix)
+	}
+	return strings.Join(parts, sep)
+}
+
+// CostOf sums the recorded payments of one node
+func CostOf(providerID int64) (*Cost, error) {
+	costs, err := sumPayments(db.DB.Where("provider_id = ?", providerID))
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := costs[providerID]; ok {
+		return c, nil
+	}
+	return &Cost{Totals: map[string]float64{}, HourlyRate: map[string]float64{}}, nil
+}
+
+// Costs sums the recorded payments of every node, by ProviderID
+func Costs() (map[int64]*Cost, error) {
+	return sumPayments(db.DB)
+}
+
+func sumPayments(q *gorm.DB) (map[int64]*Cost, error) {
+	var payments []db.Payment
+	if err := q.Order("paid_at").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	costs := make(map[int64]*Cost)
+	hours := make(map[int64]map[string]float64) // ProviderID -> unit -> hours bought
+	for _, p := range payments {
+		c, ok := costs[p.ProviderID]
+		if !ok {
+			c = &Cost{Totals: make(map[string]float64), HourlyRate: make(map[string]float64)}
+			costs[p.ProviderID] = c
+			hours[p.ProviderID] = make(map[string]float64)
+		}
+		c.Totals[p.Unit] += p.Value
+		if p.Kind == "renew" {
+			c.Renewals++
+		}
+		if d, err := time.ParseDuration(p.Duration); err == nil {
+			c.LeasedHours += d.Hours()
+			hours[p.ProviderID][p.Unit] += d.Hours()
+		}
+		c.Payments = append(c.Payments, p)
+	}
+	// A currency only pays for the hours it bought
+	for id, c := range costs {
+		for unit, h := range hours[id] {
+			if h > 0 {
+				c.HourlyRate[unit] = c.Totals[unit] / h
+			}
+		}
+	}
+	return costs, nil
+}
+
+// paymentFor turns a settlement into the Payment row for a node
+func paymentFor(providerID int64, kind, duration string, s *api.Settlement) db.Payment {
+	value, unit := s.Value()
+	return db.Payment{
+		ProviderID: providerID,
+		Kind:       kind,
+		Duration:   duration,
+		Network:    s.Network,
+		Asset:      s.Asset,
+		Amount:     s.Amount,
+		Value:      value,
+		Unit:       unit,
+		TxHash:     s.TxHash,
+		PaidAt:     s.At,
+	}
+}
+
+// Age is the time since a node was provisioned, e.g. "3d4h", "5h12m" or "40m"
+func Age(vm db.LocalVM) string {
+	d := time.Since(vm.CreatedAt)
+	switch {
+	case vm.CreatedAt.IsZero() || d < 0:
+		return "-"
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -0,0 +1,139 @@
+package fleet
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/db"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCosts(t *testing.T) {
+	registry(t)
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, p := range []db.Payment{
+		{ProviderID: 1, Kind: "renew", Duration: "24h", Value: 0.24, Unit: "USDC", PaidAt: at.Add(24 * time.Hour)},
+		{ProviderID: 1, Kind: "provision", Duration: "1h", Value: 0.01, Unit: "USDC", PaidAt: at},
+		{ProviderID: 1, Kind: "renew", Duration: "1h", Value: 0.002, Unit: "XMR", PaidAt: at.Add(48 * time.Hour)},
+		// A payment without a parseable duration adds to the total only
+		{ProviderID: 2, Kind: "provision", Duration: "", Value: 1, Unit: "USDC", PaidAt: at},
+	} {
+		if err := db.DB.Create(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	costs, err := Costs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(costs) != 2 {
+		t.Fatalf("costs for %d nodes, want 2", len(costs))
+	}
+
+	tests := []struct {
+		id       int64
+		totals   map[string]float64
+		renewals int
+		hours    float64
+		rate     map[string]float64
+		str      string
+	}{
+		{1, map[string]float64{"USDC": 0.25, "XMR": 0.002}, 2, 26,
+			map[string]float64{"USDC": 0.25 / 25, "XMR": 0.002 / 1}, "0.25 USDC+0.002 XMR"},
+		{2, map[string]float64{"USDC": 1}, 0, 0, map[string]float64{}, "1 USDC"},
+	}
+	for _, tt := range tests {
+		c := costs[tt.id]
+		if c.Renewals != tt.renewals || !near(c.LeasedHours, tt.hours) || c.String() != tt.str {
+			t.Errorf("node %d: %d renewals, %v hours, %q", tt.id, c.Renewals, c.LeasedHours, c.String())
+		}
+		if len(c.Totals) != len(tt.totals) || len(c.HourlyRate) != len(tt.rate) {
+			t.Errorf("node %d: totals %v rate %v", tt.id, c.Totals, c.HourlyRate)
+		}
+		for unit, v := range tt.totals {
+			if !near(c.Totals[unit], v) {
+				t.Errorf("node %d: %s total %v, want %v", tt.id, unit, c.Totals[unit], v)
+			}
+		}
+		for unit, v := range tt.rate {
+			if !near(c.HourlyRate[unit], v) {
+				t.Errorf("node %d: %s rate %v, want %v", tt.id, unit, c.HourlyRate[unit], v)
+			}
+		}
+	}
+
+	// Payments come back in the order they were made
+	if p := costs[1].Payments; len(p) != 3 || p[0].Kind != "provision" || p[2].Unit != "XMR" {
+		t.Errorf("payments out of order: %+v", p)
+	}
+
+	one, err := CostOf(1)
+	if err != nil || !near(one.Totals["USDC"], 0.25) {
+		t.Errorf("CostOf(1) = %+v, %v", one, err)
+	}
+	none, err := CostOf(99)
+	if err != nil || none.String() != "-" || none.FormatRate() != "-" {
+		t.Errorf("CostOf(99) = %+v, %v; want an empty cost", none, err)
+	}
+}
+
+func TestCostFormat(t *testing.T) {
+	var nilCost *Cost
+	tests := []struct {
+		name      string
+		c         *Cost
+		str, rate string
+	}{
+		{"nil", nilCost, "-", "-"},
+		{"empty", &Cost{}, "-", "-"},
+		{"one unit", &Cost{Totals: map[string]float64{"USDC": 0.42}, HourlyRate: map[string]float64{"USDC": 0.0125}}, "0.42 USDC", "0.0125 USDC/h"},
+		{"units sorted", &Cost{Totals: map[string]float64{"XMR": 0.002, "USDC": 0.1}, HourlyRate: map[string]float64{"XMR": 0.0001, "USDC": 0.005}},
+			"0.1 USDC+0.002 XMR", "0.005 USDC/h, 0.0001 XMR/h"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.String(); got != tt.str {
+			t.Errorf("%s: String() = %q, want %q", tt.name, got, tt.str)
+		}
+		if got := tt.c.FormatRate(); got != tt.rate {
+			t.Errorf("%s: FormatRate() = %q, want %q", tt.name, got, tt.rate)
+		}
+	}
+}
+
+func TestPaymentFor(t *testing.T) {
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &api.Settlement{Network: "eip155:8453", Asset: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", Amount: "240000", TxHash: "0x1", At: at}
+	p := paymentFor(7, "renew", "24h", s)
+	value, unit := s.Value()
+	if p.ProviderID != 7 || p.Kind != "renew" || p.Duration != "24h" || p.Value != value || p.Unit != unit || p.TxHash != "0x1" || !p.PaidAt.Equal(at) {
+		t.Errorf("paymentFor = %+v", p)
+	}
+}
+
+func TestAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		created time.Time
+		want    string
+	}{
+		{time.Time{}, "-"},
+		{now.Add(time.Hour), "-"},
+		{now.Add(-30 * time.Second), "0m"},
+		{now.Add(-40 * time.Minute), "40m"},
+		{now.Add(-(5*time.Hour + 12*time.Minute)), "5h12m"},
+		{now.Add(-(23*time.Hour + 59*time.Minute)), "23h59m"},
+		{now.Add(-24 * time.Hour), "1d0h"},
+		{now.Add(-(3*24*time.Hour + 4*time.Hour + 30*time.Minute)), "3d4h"},
+	}
+	for _, tt := range tests {
+		if got := Age(db.LocalVM{CreatedAt: tt.created}); got != tt.want {
+			t.Errorf("Age(created %s ago) = %q, want %q", now.Sub(tt.created).Round(time.Second), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/creds"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"gorm.io/gorm"
+)
+
+// ErrLocalCleanup wraps failures that happen after the remote teardown succeeded
+var ErrLocalCleanup = errors.New("VM destroyed on server but local cleanup failed")
+
+// Destroy tears the VM down on the orchestrator and then forgets it locally.
+// A refused teardown leaves the registry untouched.
+func Destroy(ctx context.Context, client *api.Client, vm db.LocalVM) error {
+	if err := client.Destroy(ctx, vm.ServerName); err != nil {
+		slog.Error("destroy failed", "alias", vm.Alias, "name", vm.ServerName, "err", err)
+		return err
+	}
+	slog.Info("destroyed", "alias", vm.Alias, "name", vm.ServerName)
+	if err := Forget(vm); err != nil {
+		slog.Error("local cleanup failed", "alias", vm.Alias, "err", err)
+		return fmt.Errorf("%w: %w", ErrLocalCleanup, err)
+	}
+	return nil
+}
+
+// Forget removes a VM and everything derived from it from this machine:
+// access grants, payment records, the registry row, its ssh_config block,
+// the stored root password and a per-node key.
+func Forget(vm db.LocalVM) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("provider_id = ?", vm.ProviderID).Delete(&db.AccessGrant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("provider_id = ?", vm.ProviderID).Delete(&db.Payment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&vm).Error
+	})
+	if err != nil {
+		return fmt.Errorf("local DB update failed: %w", err)
+	}
+	sshmgr.SyncConfig()
+	if err := creds.Delete(vm.ProviderID); err != nil {
+		return fmt.Errorf("failed to delete root password: %w", err)
+	}
+	if sshmgr.IsNodeKey(vm.SSHKeyPath) {
+		sshmgr.RemoveFromAgent(vm.SSHKeyPath)
+		if err := sshmgr.RemoveNodeKey(vm.SSHKeyPath); err != nil {
+			return fmt.Errorf("failed to delete per-node key: %w", err)
+		}
+	}
+	return nil
+}
+
+// PruneCredentials deletes the stored root passwords of payer's nodes whose
+// lease has run out and that the orchestrator no longer lists. remotes must
+// come from a successful /list made as payer. That list never shows another
+// identity's nodes, so those are left for a sync by their own owner. A
+// suspended node is still listed and keeps its password.
+func PruneCredentials(payer string, remotes map[int64]api.RemoteVM) {
+	var expired []db.LocalVM
+	db.DB.Where("owner_wallet = ? AND expires_at < ?", payer, time.Now().UTC()).Find(&expired)
+	for _, vm := range expired {
+		if _, listed := remotes[vm.ProviderID]; listed {
+			continue
+		}
+		if err := creds.Delete(vm.ProviderID); err != nil {
+			slog.Warn("failed to delete root password of expired node", "alias", vm.Alias, "err", err)
+		}
+	}
+}
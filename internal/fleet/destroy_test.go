@@ -0,0 +1,90 @@
+package fleet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/creds"
+	"github.com/x402-Systems/entropy/internal/db"
+
+	"github.com/zalando/go-keyring"
+)
+
+// registry opens a fresh registry under a temp HOME with a mock keyring
+func registry(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	keyring.MockInit()
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+}
+
+func TestPruneCredentials(t *testing.T) {
+	registry(t)
+	const evm, xmr = "0xabc", "xmr-derived-id"
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		vm   db.LocalVM
+		kept bool
+	}{
+		{db.LocalVM{ProviderID: 1, Alias: "expired", OwnerWallet: evm, ExpiresAt: past}, false},
+		{db.LocalVM{ProviderID: 2, Alias: "suspended", OwnerWallet: evm, ExpiresAt: past}, true},
+		{db.LocalVM{ProviderID: 3, Alias: "live", OwnerWallet: evm, ExpiresAt: future}, true},
+		// Never in evm's /list, whatever its state
+		{db.LocalVM{ProviderID: 4, Alias: "xmr-node", OwnerWallet: xmr, ExpiresAt: past}, true},
+		{db.LocalVM{ProviderID: 5, Alias: "no-owner", ExpiresAt: past}, true},
+	}
+	for _, tt := range tests {
+		if err := db.DB.Create(&tt.vm).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := creds.Store(tt.vm.ProviderID, "pw-"+tt.vm.Alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	PruneCredentials(evm, map[int64]api.RemoteVM{2: {ProviderID: 2, Status: "suspended"}, 3: {ProviderID: 3}})
+
+	for _, tt := range tests {
+		_, err := creds.Get(tt.vm.ProviderID)
+		if kept := err == nil; kept != tt.kept {
+			t.Errorf("%s: password kept = %v, want %v", tt.vm.Alias, kept, tt.kept)
+		}
+		if err != nil && !errors.Is(err, creds.ErrNotFound) {
+			t.Errorf("%s: %v", tt.vm.Alias, err)
+		}
+	}
+
+	// A sync as the Monero identity prunes its own expired node
+	PruneCredentials(xmr, map[int64]api.RemoteVM{})
+	if _, err := creds.Get(4); !errors.Is(err, creds.ErrNotFound) {
+		t.Errorf("xmr-node password survived a sync by its owner: %v", err)
+	}
+}
+
+// Lease times are stored in UTC; west of UTC a local bound would keep the
+// password of a lapsed node for hours
+func TestPruneCredentialsWestOfUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("EST", -5*3600)
+	t.Cleanup(func() { time.Local = local })
+
+	registry(t)
+	vm := db.LocalVM{ProviderID: 1, Alias: "lapsed", OwnerWallet: "0xabc", ExpiresAt: time.Now().Add(-30 * time.Minute)}
+	if err := db.DB.Create(&vm).Error; err != nil {
+		t.Fatal(err)
+	}
+	creds.Store(1, "pw")
+
+	PruneCredentials("0xabc", map[int64]api.RemoteVM{})
+	if _, err := creds.Get(1); !errors.Is(err, creds.ErrNotFound) {
+		t.Errorf("password of a node that lapsed 30 minutes ago survived: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/creds"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/flock"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"gorm.io/gorm"
+)
+
+// ErrAliasTaken is returned before any payment when the alias is already registered
+var ErrAliasTaken = errors.New("alias already exists in the local registry")
+
+// ProvisionRequest is the input shared by `entropy up` and the TUI form
+type ProvisionRequest struct {
+	Alias    string
+	Tier     string
+	Distro   string
+	Region   string
+	Duration string
+
+	// SSHKeyPath is a public key file; empty uses the shared default key
+	SSHKeyPath string
+	// PerNodeKey generates a dedicated keypair, optionally encrypted with KeyPassphrase
+	PerNodeKey    bool
+	KeyPassphrase []byte
+}
+
+type ProvisionResult struct {
+	Response *api.ProvisionResponse
+	VM       db.LocalVM
+	// SaveErr is set when the VM exists remotely but could not be recorded locally
+	SaveErr error
+	// PasswordStored reports whether the root password went into the keyring
+	PasswordStored bool
+	// NewKey is the default public key when this provision generated it
+	NewKey string
+}
+
+// Provision resolves the SSH key, runs the eligibility check, pays for the
+// VM and records it in the local registry.
+func Provision(ctx context.Context, client *api.Client, req ProvisionRequest) (*ProvisionResult, error) {
+	if req.PerNodeKey && req.SSHKeyPath != "" {
+		return nil, fmt.Errorf("a per-node key cannot be combined with an explicit key path")
+	}
+
+	if req.Alias != "" {
+		// Held until the row is written, so a second `up` with the same alias
+		// is refused before it can pay
+		lock, err := flock.TryLock("provision-" + req.Alias)
+		if errors.Is(err, flock.ErrLocked) {
+			return nil, fmt.Errorf("provisioning of %s %w", req.Alias, ErrInProgress)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+
+		var count int64
+		db.DB.Model(&db.LocalVM{}).Where("alias = ?", req.Alias).Count(&count)
+		if count > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAliasTaken, req.Alias)
+		}
+	}
+
+	keyPath, created := req.SSHKeyPath, false
+	switch {
+	case req.PerNodeKey:
+		var err error
+		keyPath, err = sshmgr.GenerateNodeKey(req.KeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("SSH key manager error: %w", err)
+		}
+	case keyPath == "":
+		var err error
+		keyPath, created, err = sshmgr.GetDefaultKey()
+		if err != nil {
+			return nil, fmt.Errorf("SSH key manager error: %w", err)
+		}
+		if created {
+			slog.Info("generated default SSH keypair", "path", keyPath)
+		}
+	}
+
+	// The pending key is dropped if nothing was paid for. Once /provision has
+	// been sent the node may exist even if its answer never arrived.
+	keepKey := false
+	if req.PerNodeKey {
+		pendingKey := keyPath
+		defer func() {
+			if !keepKey {
+				sshmgr.RemoveNodeKey(pendingKey)
+			}
+		}()
+	}
+
+	keyContent, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key file [%s]: %w", keyPath, err)
+	}
+
+	params := api.ProvisionParams{
+		Tier:     req.Tier,
+		Distro:   req.Distro,
+		Region:   req.Region,
+		Duration: req.Duration,
+		SSHKey:   strings.TrimSpace(string(keyContent)),
+	}
+
+	if err := client.Validate(ctx, params); err != nil {
+		slog.Warn("provision refused by eligibility check", "alias", req.Alias, "err", err)
+		return nil, err
+	}
+
+	keepKey = true
+	resp, err := client.Provision(ctx, params)
+	if err != nil {
+		slog.Error("provision failed", "alias", req.Alias, "tier", req.Tier, "region", req.Region, "err", err)
+		if req.PerNodeKey {
+			return nil, fmt.Errorf("%w (the node may still have been created; its key was kept at %s)", err, sshmgr.PrivateKeyPath(keyPath))
+		}
+		return nil, err
+	}
+	slog.Info("provisioned", "alias", req.Alias, "provider_id", resp.VM.ProviderID, "name", resp.VM.Name, "tier", resp.VM.Tier, "region", resp.VM.Region)
+
+	result := &ProvisionResult{Response: resp}
+	if created {
+		result.NewKey = keyPath
+	}
+
+	if req.PerNodeKey {
+		adopted, err := sshmgr.AdoptNodeKey(keyPath, resp.VM.ProviderID)
+		if err != nil {
+			result.SaveErr = fmt.Errorf("failed to file per-node key under ProviderID: %w", err)
+		}
+		keyPath = adopted
+	}
+
+	result.VM = db.LocalVM{
+		ProviderID:  resp.VM.ProviderID,
+		ServerName:  resp.VM.Name,
+		Alias:       req.Alias,
+		IP:          resp.VM.IP,
+		Tier:        resp.VM.Tier,
+		Region:      resp.VM.Region,
+		ExpiresAt:   resp.VM.ExpiresAt,
+		SSHKeyPath:  keyPath,
+		OwnerWallet: client.PayerID,
+	}
+	if result.VM.Alias == "" {
+		result.VM.Alias = resp.VM.Name
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&result.VM).Error; err != nil {
+			return err
+		}
+		if resp.Settlement == nil {
+			return nil
+		}
+		payment := paymentFor(resp.VM.ProviderID, "provision", req.Duration, resp.Settlement)
+		return tx.Create(&payment).Error
+	})
+	if err != nil {
+		result.SaveErr = errors.Join(result.SaveErr, err)
+	}
+	if resp.VM.Password != "" {
+		if err := creds.Store(resp.VM.ProviderID, resp.VM.Password); err != nil {
+			result.SaveErr = errors.Join(result.SaveErr, fmt.Errorf("failed to store root password in keyring: %w", err))
+		} else {
+			result.PasswordStored = true
+		}
+	}
+	if result.SaveErr != nil {
+		slog.Error("provisioned VM not fully recorded locally", "alias", result.VM.Alias, "err", result.SaveErr)
+	}
+	sshmgr.SyncConfig()
+
+	return result, nil
+}
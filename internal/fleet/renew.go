@@ -0,0 +1,64 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/flock"
+
+	"gorm.io/gorm"
+)
+
+// ErrInProgress is returned when the same operation is already running in
+// this or another entropy process
+var ErrInProgress = errors.New("already in progress")
+
+// ErrNotRecorded wraps a local failure after the orchestrator accepted a renewal
+var ErrNotRecorded = errors.New("renewed on server but the local registry was not updated")
+
+// Renew pays for a lease extension and records the new expiry and payment. Only one
+// renewal per node runs at a time, so a double click never pays twice.
+func Renew(ctx context.Context, client *api.Client, vm db.LocalVM, duration string) (*api.RenewResponse, error) {
+	lock, err := flock.TryLock("renew-" + strconv.FormatInt(vm.ProviderID, 10))
+	if errors.Is(err, flock.ErrLocked) {
+		return nil, fmt.Errorf("renewal of %s %w", vm.Alias, ErrInProgress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	res, err := client.Renew(ctx, vm.ServerName, duration)
+	if err != nil {
+		slog.Error("renew failed", "alias", vm.Alias, "duration", duration, "err", err)
+		return nil, err
+	}
+	slog.Info("renewed", "alias", vm.Alias, "duration", duration, "new_expiry", res.NewExpiry)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if expiry, ok := res.ExpiresAt(); ok {
+			// Never move a lease backwards if a later renewal was recorded first.
+			// Stored times are UTC, see LocalVM.BeforeSave.
+			err := tx.Model(&db.LocalVM{}).Where("id = ? AND expires_at < ?", vm.ID, expiry.UTC()).
+				Update("expires_at", expiry.UTC()).Error
+			if err != nil {
+				return err
+			}
+		}
+		if res.Settlement == nil {
+			return nil
+		}
+		payment := paymentFor(vm.ProviderID, "renew", duration, res.Settlement)
+		return tx.Create(&payment).Error
+	})
+	if err != nil {
+		slog.Error("renewal not recorded locally", "alias", vm.Alias, "err", err)
+		return res, fmt.Errorf("%w: %w", ErrNotRecorded, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+// Package flock provides advisory locks that keep two entropy processes (or
+// two goroutines of one) from running the same operation at once.
+package flock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/config"
+)
+
+// ErrLocked is returned by TryLock when the lock is already held
+var ErrLocked = errors.New("held by another entropy process")
+
+// Lock is a held advisory lock
+type Lock struct {
+	f *os.File
+}
+
+// Path is the lock file for name under ~/.config/entropy/locks
+func Path(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+	return filepath.Join(config.Dir(), "locks", safe+".lock")
+}
+
+// TryLock takes the lock called name without waiting. The lock is released
+// by Unlock or when the process exits, however it exits.
+func TryLock(name string) (*Lock, error) {
+	return take(name, false)
+}
+
+// Acquire takes the lock called name, waiting for whoever holds it
+func Acquire(name string) (*Lock, error) {
+	return take(name, true)
+}
+
+func take(name string, wait bool) (*Lock, error) {
+	path := Path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, wait); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Lock{f: f}, nil
+}
+
+// Unlock releases the lock. The file is left in place: removing it would
+// let a waiter lock an unlinked inode while a newcomer locks a fresh one.
+func (l *Lock) Unlock() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	err := unlockFile(l.f)
+	l.f.Close()
+	l.f = nil
+	return err
+}
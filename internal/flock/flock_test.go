@@ -0,0 +1,119 @@
+package flock
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is not a test: TestTryLockAcrossProcesses runs the test
+// binary again with ENTROPY_FLOCK_HELPER set to try the lock from a second
+// process. The exit code is 3 when the lock was held.
+func TestHelperProcess(t *testing.T) {
+	name := os.Getenv("ENTROPY_FLOCK_HELPER")
+	if name == "" {
+		return
+	}
+	l, err := TryLock(name)
+	switch {
+	case errors.Is(err, ErrLocked):
+		os.Exit(3)
+	case err != nil:
+		os.Exit(1)
+	}
+	l.Unlock()
+	os.Exit(0)
+}
+
+func tryInChild(t *testing.T, name string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "ENTROPY_FLOCK_HELPER="+name)
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 0
+}
+
+func tempHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+}
+
+func TestTryLockAcrossProcesses(t *testing.T) {
+	tempHome(t)
+	l, err := TryLock("renew-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := tryInChild(t, "renew-42"); code != 3 {
+		t.Fatalf("child exited %d while the lock was held, want 3 (ErrLocked)", code)
+	}
+	if code := tryInChild(t, "renew-43"); code != 0 {
+		t.Errorf("child exited %d on an unrelated lock", code)
+	}
+
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if code := tryInChild(t, "renew-42"); code != 0 {
+		t.Errorf("child exited %d after Unlock, want 0", code)
+	}
+}
+
+func TestAcquireWaits(t *testing.T) {
+	tempHome(t)
+	held, err := TryLock("registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryLock("registry"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second TryLock = %v, want ErrLocked", err)
+	}
+
+	got := make(chan *Lock)
+	go func() {
+		l, err := Acquire("registry")
+		if err != nil {
+			t.Error(err)
+		}
+		got <- l
+	}()
+	select {
+	case <-got:
+		t.Fatal("Acquire returned while the lock was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	held.Unlock()
+	select {
+	case l := <-got:
+		l.Unlock()
+	case <-time.After(5 * time.Second):
+		t.Fatal("Acquire did not return after Unlock")
+	}
+}
+
+func TestPath(t *testing.T) {
+	tempHome(t)
+	tests := map[string]string{
+		"renew-42":     "renew-42.lock",
+		"registry":     "registry.lock",
+		"../../etc/x":  ".._.._etc_x.lock",
+		"a b/c\\d:e*f": "a_b_c_d_e_f.lock",
+	}
+	for name, want := range tests {
+		if got := Path(name); filepath.Base(got) != want || filepath.Base(filepath.Dir(got)) != "locks" {
+			t.Errorf("Path(%q) = %s, want locks/%s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package shred overwrites files before deleting them, so removed secrets are
+// not left readable in freed blocks.
+package shred
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+)
+
+// File overwrites path with random bytes and removes it. On SSDs and
+// copy-on-write filesystems the overwrite is best effort. A file that could
+// be removed but not overwritten is still gone and the error says so.
+func File(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var info os.FileInfo
+		if info, err = f.Stat(); err == nil {
+			_, err = io.CopyN(f, rand.Reader, info.Size())
+		}
+		if err == nil {
+			err = f.Sync()
+		}
+		f.Close()
+	}
+	if rmErr := os.Remove(path); rmErr != nil {
+		return rmErr
+	}
+	if err != nil {
+		return fmt.Errorf("removed but not overwritten: %w", err)
+	}
+	return nil
+}
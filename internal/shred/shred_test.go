@@ -0,0 +1,37 @@
+package shred
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entropy.db")
+	if err := os.WriteFile(path, []byte("plaintext registry"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A second link sees the blocks the first name pointed at
+	peek := path + ".link"
+	linked := os.Link(path, peek) == nil
+
+	if err := File(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s still exists: %v", path, err)
+	}
+	if linked {
+		data, err := os.ReadFile(peek)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != len("plaintext registry") || string(data) == "plaintext registry" {
+			t.Errorf("contents were not overwritten in place: %q", data)
+		}
+	}
+
+	if err := File(path); !os.IsNotExist(err) {
+		t.Errorf("File on a missing path = %v, want a not-exist error", err)
+	}
+}
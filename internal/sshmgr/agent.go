@@ -0,0 +1,112 @@
+package sshmgr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// PassphraseFunc is asked for a passphrase only when the private key is encrypted
+type PassphraseFunc func() ([]byte, error)
+
+// LoadSigner parses a private key, decrypting it through prompt if required
+func LoadSigner(keyPath string, prompt PassphraseFunc) (ssh.Signer, error) {
+	raw, err := loadRawKey(keyPath, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(raw)
+}
+
+// AddToAgent loads the private key behind keyPath into the running ssh-agent.
+// Keys already held by the agent are not added twice.
+func AddToAgent(keyPath string, prompt PassphraseFunc) error {
+	conn, err := dialAgent()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := agent.NewClient(conn)
+
+	if pub, err := readPublicKey(keyPath); err == nil {
+		held, err := client.List()
+		if err != nil {
+			return err
+		}
+		for _, k := range held {
+			if bytes.Equal(k.Marshal(), pub.Marshal()) {
+				return nil
+			}
+		}
+	}
+
+	raw, err := loadRawKey(keyPath, prompt)
+	if err != nil {
+		return err
+	}
+
+	return client.Add(agent.AddedKey{
+		PrivateKey: raw,
+		Comment:    "entropy:" + PrivateKeyPath(keyPath),
+	})
+}
+
+// RemoveFromAgent drops the key from the running agent. It is best effort:
+// a missing agent or an unknown key is not an error during teardown.
+func RemoveFromAgent(keyPath string) {
+	pub, err := readPublicKey(keyPath)
+	if err != nil {
+		return
+	}
+	conn, err := dialAgent()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	agent.NewClient(conn).Remove(pub)
+}
+
+func dialAgent() (net.Conn, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, fmt.Errorf("no ssh-agent running (SSH_AUTH_SOCK is unset)")
+	}
+	return net.Dial("unix", sock)
+}
+
+func loadRawKey(keyPath string, prompt PassphraseFunc) (interface{}, error) {
+	pemBytes, err := os.ReadFile(PrivateKeyPath(keyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := ssh.ParseRawPrivateKey(pemBytes)
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return raw, err
+	}
+	if prompt == nil {
+		return nil, fmt.Errorf("key %s is passphrase protected", PrivateKeyPath(keyPath))
+	}
+
+	passphrase, err := prompt()
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+}
+
+func readPublicKey(keyPath string) (ssh.PublicKey, error) {
+	data, err := os.ReadFile(PrivateKeyPath(keyPath) + ".pub")
+	if err != nil {
+		return nil, err
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	return pub, err
+}
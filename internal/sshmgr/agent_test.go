@@ -0,0 +1,129 @@
+package sshmgr
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// serveAgent runs an in-memory ssh-agent on a unix socket and counts the
+// connections that are still open
+func serveAgent(t *testing.T, keys ...ed25519.PrivateKey) *atomic.Int32 {
+	t.Helper()
+	keyring := agent.NewKeyring()
+	for _, k := range keys {
+		if err := keyring.Add(agent.AddedKey{PrivateKey: k}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	var open atomic.Int32
+	var wg sync.WaitGroup
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			open.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				agent.ServeAgent(keyring, conn)
+				conn.Close()
+				open.Add(-1)
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		l.Close()
+		wg.Wait()
+	})
+	return &open
+}
+
+func writeKeyPair(t *testing.T, dir, name string) (ed25519.PrivateKey, string) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".pub", ssh.MarshalAuthorizedKey(pub), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return priv, path + ".pub"
+}
+
+func waitOpen(t *testing.T, open *atomic.Int32, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for open.Load() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d agent connection(s) open, want %d", open.Load(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAgentSignerClosesConnection(t *testing.T) {
+	dir := t.TempDir()
+	held, heldPub := writeKeyPair(t, dir, "held")
+	_, otherPub := writeKeyPair(t, dir, "other")
+	open := serveAgent(t, held)
+
+	for i := 0; i < 5; i++ {
+		if signer, conn := agentSigner(otherPub); signer != nil || conn != nil {
+			t.Fatal("agentSigner matched a key the agent does not hold")
+		}
+	}
+	waitOpen(t, open, 0)
+
+	signer, conn := agentSigner(heldPub)
+	if signer == nil || conn == nil {
+		t.Fatal("agentSigner did not find the held key")
+	}
+	if _, err := signer.Sign(rand.Reader, []byte("challenge")); err != nil {
+		t.Fatalf("sign through agent: %v", err)
+	}
+	waitOpen(t, open, 1)
+
+	conn.Close()
+	waitOpen(t, open, 0)
+}
+
+func TestAgentSignerWithoutAgent(t *testing.T) {
+	_, pub := writeKeyPair(t, t.TempDir(), "k")
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if signer, conn := agentSigner(pub); signer != nil || conn != nil {
+		t.Fatal("agentSigner returned a signer without an agent")
+	}
+}
@@ -0,0 +1,194 @@
+package sshmgr
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/x402-Systems/entropy/internal/config"
+	"github.com/x402-Systems/entropy/internal/db"
+)
+
+const managedHeader = "# Managed by entropy. Regenerated on up, rm and sync; do not edit."
+
+// ConfigPath is the managed include file holding one Host block per live node
+func ConfigPath() string {
+	return filepath.Join(config.Dir(), "ssh_config")
+}
+
+// PrivateKeyPath maps a stored key path (usually the .pub) to its private half
+func PrivateKeyPath(keyPath string) string {
+	return strings.TrimSuffix(keyPath, ".pub")
+}
+
+// SSHArgs are the OpenSSH client arguments for a root login on a node.
+// An empty keyPath leaves key selection to ssh-agent.
+func SSHArgs(ip, keyPath string) []string {
+	var args []string
+	if keyPath != "" {
+		args = append(args, "-i", PrivateKeyPath(keyPath))
+	}
+	return append(args,
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-o", "LogLevel=ERROR",
+		"root@"+ip,
+	)
+}
+
+// SyncConfig rewrites the managed include file from the local registry.
+// It returns the number of Host blocks written.
+func SyncConfig() (int, error) {
+	vms, err := db.LiveVMs()
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	b.WriteString(managedHeader + "\n")
+
+	written := 0
+	for _, vm := range vms {
+		if !configSafe(vm) {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\nHost %s\n", vm.Alias)
+		fmt.Fprintf(&b, "    HostName %s\n", vm.IP)
+		b.WriteString("    User root\n")
+		if vm.SSHKeyPath != "" {
+			fmt.Fprintf(&b, "    IdentityFile %s\n", quoteConfigValue(PrivateKeyPath(vm.SSHKeyPath)))
+			b.WriteString("    IdentitiesOnly yes\n")
+		}
+		// Ephemeral IPs get recycled between leases, so host keys are never pinned
+		b.WriteString("    StrictHostKeyChecking no\n")
+		b.WriteString("    UserKnownHostsFile /dev/null\n")
+		b.WriteString("    LogLevel ERROR\n")
+		written++
+	}
+
+	if err := os.MkdirAll(config.Dir(), 0700); err != nil {
+		return 0, err
+	}
+
+	tmp := ConfigPath() + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return 0, err
+	}
+	return written, os.Rename(tmp, ConfigPath())
+}
+
+// configSafe reports whether a node can be written as a Host block. Aliases,
+// IPs and key paths come from the orchestrator or imported bundles, so anything
+// that could end the line and start another directive is refused.
+func configSafe(vm db.LocalVM) bool {
+	if vm.Alias == "" || net.ParseIP(vm.IP) == nil {
+		return false
+	}
+	// Host patterns are whitespace separated and treat these as wildcards
+	if strings.ContainsAny(vm.Alias, "*?!,\"") {
+		return false
+	}
+	for _, r := range vm.Alias {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	// IdentityFile may contain spaces (it is quoted) but nothing that breaks the line
+	if strings.ContainsRune(vm.SSHKeyPath, '"') {
+		return false
+	}
+	for _, r := range vm.SSHKeyPath {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// InstallInclude adds an Include for the managed file to ~/.ssh/config.
+// It returns false if the line was already present.
+func InstallInclude() (bool, error) {
+	home, _ := os.UserHomeDir()
+	sshDir := filepath.Join(home, ".ssh")
+	userConfig := filepath.Join(sshDir, "config")
+
+	includeLine := "Include " + quoteConfigValue(ConfigPath())
+
+	existing, err := os.ReadFile(userConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == includeLine {
+			return false, nil
+		}
+	}
+
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return false, err
+	}
+
+	// Include must come before the first Host block, otherwise it is scoped to it
+	updated := includeLine + "\n"
+	if len(existing) > 0 {
+		updated += "\n" + string(existing)
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(userConfig); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return true, os.WriteFile(userConfig, []byte(updated), mode)
+}
+
+// RemoveInclude drops the managed Include line from ~/.ssh/config. It returns
+// false if the line was not present.
+func RemoveInclude() (bool, error) {
+	home, _ := os.UserHomeDir()
+	userConfig := filepath.Join(home, ".ssh", "config")
+	includeLine := "Include " + quoteConfigValue(ConfigPath())
+
+	info, err := os.Stat(userConfig)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	existing, err := os.ReadFile(userConfig)
+	if err != nil {
+		return false, err
+	}
+
+	lines := strings.Split(string(existing), "\n")
+	kept := lines[:0]
+	removed := false
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == includeLine {
+			removed = true
+			// InstallInclude separates the line from the rest with a blank one
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+				i++
+			}
+			continue
+		}
+		kept = append(kept, lines[i])
+	}
+	if !removed {
+		return false, nil
+	}
+	return true, os.WriteFile(userConfig, []byte(strings.Join(kept, "\n")), info.Mode().Perm())
+}
+
+func quoteConfigValue(v string) string {
+	if strings.ContainsAny(v, " \t") {
+		return `"` + v + `"`
+	}
+	return v
+}
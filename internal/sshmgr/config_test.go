@@ -0,0 +1,93 @@
+package sshmgr
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+)
+
+func TestConfigSafe(t *testing.T) {
+	tests := []struct {
+		name string
+		vm   db.LocalVM
+		want bool
+	}{
+		{"plain", db.LocalVM{Alias: "web-1", IP: "203.0.113.7"}, true},
+		{"ipv6", db.LocalVM{Alias: "web-1", IP: "2001:db8::1"}, true},
+		{"key with space", db.LocalVM{Alias: "web-1", IP: "203.0.113.7", SSHKeyPath: "/home/a b/.ssh/id.pub"}, true},
+		{"empty alias", db.LocalVM{IP: "203.0.113.7"}, false},
+		{"allocating", db.LocalVM{Alias: "web-1", IP: "IP-Allocating"}, false},
+		{"ip injection", db.LocalVM{Alias: "web-1", IP: "203.0.113.7\n    ProxyCommand sh -c id"}, false},
+		{"alias newline", db.LocalVM{Alias: "web-1\nProxyCommand id", IP: "203.0.113.7"}, false},
+		{"alias carriage return", db.LocalVM{Alias: "web-1\rx", IP: "203.0.113.7"}, false},
+		{"alias space", db.LocalVM{Alias: "web 1", IP: "203.0.113.7"}, false},
+		{"alias non-breaking space", db.LocalVM{Alias: "web\u00a01", IP: "203.0.113.7"}, false},
+		{"alias wildcard", db.LocalVM{Alias: "web-*", IP: "203.0.113.7"}, false},
+		{"alias negation", db.LocalVM{Alias: "!web", IP: "203.0.113.7"}, false},
+		{"key newline", db.LocalVM{Alias: "web-1", IP: "203.0.113.7", SSHKeyPath: "/k\nProxyCommand id"}, false},
+		{"key quote", db.LocalVM{Alias: "web-1", IP: "203.0.113.7", SSHKeyPath: `/k" ProxyCommand "id`}, false},
+	}
+	for _, tt := range tests {
+		if got := configSafe(tt.vm); got != tt.want {
+			t.Errorf("%s: configSafe = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteConfigValue(t *testing.T) {
+	tests := map[string]string{
+		"/root/.ssh/id":    "/root/.ssh/id",
+		"/home/a b/.ssh/k": `"/home/a b/.ssh/k"`,
+		"C:\\Users\\a\tb":  "\"C:\\Users\\a\tb\"",
+	}
+	for in, want := range tests {
+		if got := quoteConfigValue(in); got != want {
+			t.Errorf("quoteConfigValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestSyncConfigAcrossTimeZones runs with the machine clock east and west of
+// UTC. Leases from the orchestrator arrive in UTC; ones computed locally
+// carry the local offset.
+func TestSyncConfigAcrossTimeZones(t *testing.T) {
+	for _, zone := range []*time.Location{time.FixedZone("JST", 9*3600), time.FixedZone("EST", -5*3600)} {
+		t.Run(zone.String(), func(t *testing.T) {
+			local := time.Local
+			time.Local = zone
+			t.Cleanup(func() { time.Local = local })
+
+			t.Setenv("HOME", t.TempDir())
+			if err := db.Init(); err != nil {
+				t.Fatal(err)
+			}
+			now := time.Now()
+			for _, vm := range []db.LocalVM{
+				{ProviderID: 1, Alias: "utc-live", IP: "203.0.113.1", ExpiresAt: now.Add(30 * time.Minute).UTC()},
+				{ProviderID: 2, Alias: "local-live", IP: "203.0.113.2", ExpiresAt: now.Add(30 * time.Minute).In(zone)},
+				{ProviderID: 3, Alias: "utc-expired", IP: "203.0.113.3", ExpiresAt: now.Add(-30 * time.Minute).UTC()},
+				{ProviderID: 4, Alias: "local-expired", IP: "203.0.113.4", ExpiresAt: now.Add(-30 * time.Minute).In(zone)},
+			} {
+				if err := db.DB.Create(&vm).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			n, err := SyncConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(ConfigPath())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := string(data)
+			if n != 2 || !strings.Contains(got, "Host local-live\n") || !strings.Contains(got, "Host utc-live\n") {
+				t.Errorf("SyncConfig wrote %d blocks, want local-live and utc-live:\n%s", n, got)
+			}
+		})
+	}
+}
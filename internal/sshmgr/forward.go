@@ -0,0 +1,123 @@
+package sshmgr
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const keepaliveInterval = 15 * time.Second
+
+// EnableGatewayPorts lets remote forwards bind the node's public interface.
+// sshd is only reloaded when the setting actually changes.
+func EnableGatewayPorts(c *ssh.Client) error {
+	script := `set -e
+conf=/etc/ssh/sshd_config.d/99-entropy-expose.conf
+if [ "$(sshd -T 2>/dev/null | awk '/^gatewayports/ {print $2}')" = "clientspecified" ]; then exit 0; fi
+mkdir -p /etc/ssh/sshd_config.d
+printf 'GatewayPorts clientspecified\n' > "$conf"
+if ! grep -qi '^Include /etc/ssh/sshd_config.d' /etc/ssh/sshd_config; then
+  sed -i '1i Include /etc/ssh/sshd_config.d/*.conf' /etc/ssh/sshd_config
+fi
+sshd -t
+systemctl reload ssh 2>/dev/null || systemctl reload sshd 2>/dev/null || service ssh reload`
+	_, err := Run(c, script, nil)
+	return err
+}
+
+// RemoteForward listens on remoteAddr on the node and pipes every accepted
+// connection to localAddr. It blocks until ctx is done or the SSH connection drops.
+func RemoteForward(ctx context.Context, c *ssh.Client, remoteAddr, localAddr string) error {
+	// The helpers below stop when this call returns, not only when the caller's
+	// ctx ends, so a reconnect loop does not pile them up
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ln, err := c.Listen("tcp", remoteAddr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	// A silent network drop never fails Accept on its own, so probe the link
+	go func() {
+		t := time.NewTicker(keepaliveInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				replied := make(chan error, 1)
+				go func() {
+					_, _, err := c.SendRequest("keepalive@openssh.com", true, nil)
+					replied <- err
+				}()
+				select {
+				case err := <-replied:
+					if err == nil {
+						continue
+					}
+				case <-time.After(keepaliveInterval):
+				}
+				c.Close()
+				return
+			}
+		}
+	}()
+
+	// Wait returns once the transport is gone, which also fails Accept
+	dropped := make(chan error, 1)
+	go func() { dropped <- c.Wait() }()
+
+	for {
+		remote, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			select {
+			case werr := <-dropped:
+				if werr == nil {
+					werr = io.EOF
+				}
+				return werr
+			default:
+				return err
+			}
+		}
+		go pipe(remote, localAddr)
+	}
+}
+
+func pipe(remote net.Conn, localAddr string) {
+	defer remote.Close()
+
+	local, err := net.Dial("tcp", localAddr)
+	if err != nil {
+		return
+	}
+	defer local.Close()
+
+	// Tear down both sides as soon as either direction finishes
+	var once sync.Once
+	done := make(chan struct{})
+	closeBoth := func() { once.Do(func() { close(done) }) }
+	go func() {
+		io.Copy(local, remote)
+		closeBoth()
+	}()
+	go func() {
+		io.Copy(remote, local)
+		closeBoth()
+	}()
+	<-done
+}
@@ -0,0 +1,135 @@
+package sshmgr
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// forwardingServer accepts one SSH connection on conn, grants any remote
+// forward and hangs up when drop is closed. The returned channel receives
+// once a forward was granted.
+func forwardingServer(t *testing.T, conn net.Conn, drop <-chan struct{}) <-chan struct{} {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+	granted := make(chan struct{}, 1)
+
+	go func() {
+		sc, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+		go func() {
+			for nc := range chans {
+				nc.Reject(ssh.Prohibited, "no channels")
+			}
+		}()
+		go func() {
+			for r := range reqs {
+				r.Reply(r.Type == "tcpip-forward", nil)
+				if r.Type == "tcpip-forward" {
+					granted <- struct{}{}
+				}
+			}
+		}()
+		<-drop
+		sc.Close()
+	}()
+	return granted
+}
+
+// tcpPair returns both ends of a loopback connection. net.Pipe would block
+// the SSH handshake, where both sides send their version first.
+func tcpPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+// forwardGoroutines counts goroutines started by RemoteForward
+func forwardGoroutines() int {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	return strings.Count(string(buf), "sshmgr.RemoteForward.func")
+}
+
+// expose reconnects in a loop on the same ctx; every dropped session must
+// leave nothing behind
+func TestRemoteForwardReconnectsDoNotLeak(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < 5; i++ {
+		clientConn, serverConn := tcpPair(t)
+		drop := make(chan struct{})
+		granted := forwardingServer(t, serverConn, drop)
+
+		sc, chans, reqs, err := ssh.NewClientConn(clientConn, "node", &ssh.ClientConfig{
+			User:            "root",
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := ssh.NewClient(sc, chans, reqs)
+
+		before := forwardGoroutines()
+		done := make(chan error, 1)
+		go func() { done <- RemoteForward(ctx, client, "0.0.0.0:8080", "127.0.0.1:1") }()
+		select {
+		case <-granted:
+		case err := <-done:
+			t.Fatalf("RemoteForward = %v before the forward was granted", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no remote forward requested")
+		}
+		// The listener only registers once the reply is read; a drop before
+		// that would leave Accept waiting in x/crypto/ssh
+		for forwardGoroutines() < before+2 {
+			time.Sleep(time.Millisecond)
+		}
+		close(drop)
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("RemoteForward did not return after the connection dropped")
+		}
+		client.Close()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for forwardGoroutines() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := forwardGoroutines(); n > 0 {
+		t.Errorf("%d RemoteForward goroutines still running after 5 dropped sessions", n)
+	}
+}
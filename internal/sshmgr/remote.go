@@ -0,0 +1,190 @@
+package sshmgr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const dialTimeout = 15 * time.Second
+
+// Connect opens a root session to a node. A matching key already held by
+// ssh-agent is preferred so encrypted keys don't prompt twice.
+func Connect(ip, keyPath string, prompt PassphraseFunc) (*ssh.Client, error) {
+	if signer, agentConn := agentSigner(keyPath); signer != nil {
+		client, err := DialWithSigner(ip, signer)
+		if err != nil {
+			agentConn.Close()
+			return nil, err
+		}
+		// Signing goes through the agent, so its socket lives as long as the session
+		go func() {
+			client.Wait()
+			agentConn.Close()
+		}()
+		return client, nil
+	}
+
+	signer, err := LoadSigner(keyPath, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return DialWithSigner(ip, signer)
+}
+
+// DialWithSigner authenticates with exactly one key, so a successful dial
+// proves that key is accepted by the node.
+func DialWithSigner(ip string, signer ssh.Signer) (*ssh.Client, error) {
+	cfg := &ssh.ClientConfig{
+		User: "root",
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		// Ephemeral IPs get recycled between leases; same policy as `entropy ssh`
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         dialTimeout,
+	}
+	return ssh.Dial("tcp", net.JoinHostPort(ip, "22"), cfg)
+}
+
+// Run executes command on the node and returns its combined output
+func Run(c *ssh.Client, command string, stdin io.Reader) ([]byte, error) {
+	session, err := c.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var out bytes.Buffer
+	session.Stdout = &out
+	session.Stderr = &out
+	session.Stdin = stdin
+
+	if err := session.Run(command); err != nil {
+		return out.Bytes(), fmt.Errorf("%s: %w", strings.TrimSpace(out.String()), err)
+	}
+	return out.Bytes(), nil
+}
+
+// ReadAuthorizedKeys returns root's authorized_keys, one entry per line
+func ReadAuthorizedKeys(c *ssh.Client) ([]string, error) {
+	out, err := Run(c, "cat ~/.ssh/authorized_keys 2>/dev/null || true", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// WriteAuthorizedKeys atomically replaces root's authorized_keys
+func WriteAuthorizedKeys(c *ssh.Client, lines []string) error {
+	content := strings.Join(lines, "\n") + "\n"
+	script := "umask 077 && mkdir -p ~/.ssh && cat > ~/.ssh/authorized_keys.entropy && mv ~/.ssh/authorized_keys.entropy ~/.ssh/authorized_keys"
+	_, err := Run(c, script, strings.NewReader(content))
+	return err
+}
+
+// AddAuthorizedKey appends line unless its key is already authorized
+func AddAuthorizedKey(c *ssh.Client, line string) error {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	lines, err := ReadAuthorizedKeys(c)
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		if sameKey(l, pub) {
+			return nil
+		}
+	}
+	return WriteAuthorizedKeys(c, append(lines, strings.TrimSpace(line)))
+}
+
+// ReplaceAuthorizedKey installs line, replacing any existing entry for the same
+// key so changed options (such as an expiry) take effect
+func ReplaceAuthorizedKey(c *ssh.Client, line string) error {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	lines, err := ReadAuthorizedKeys(c)
+	if err != nil {
+		return err
+	}
+	kept := lines[:0]
+	for _, l := range lines {
+		if !sameKey(l, pub) {
+			kept = append(kept, l)
+		}
+	}
+	return WriteAuthorizedKeys(c, append(kept, strings.TrimSpace(line)))
+}
+
+// RemoveAuthorizedKey drops every entry for pub. It reports whether anything was removed.
+func RemoveAuthorizedKey(c *ssh.Client, pub ssh.PublicKey) (bool, error) {
+	lines, err := ReadAuthorizedKeys(c)
+	if err != nil {
+		return false, err
+	}
+
+	kept := lines[:0]
+	for _, l := range lines {
+		if !sameKey(l, pub) {
+			kept = append(kept, l)
+		}
+	}
+	if len(kept) == len(lines) {
+		return false, nil
+	}
+	return true, WriteAuthorizedKeys(c, kept)
+}
+
+// PublicKey reads the public half stored next to a key path
+func PublicKey(keyPath string) (ssh.PublicKey, error) {
+	return readPublicKey(keyPath)
+}
+
+func sameKey(line string, pub ssh.PublicKey) bool {
+	k, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	return err == nil && bytes.Equal(k.Marshal(), pub.Marshal())
+}
+
+// agentSigner finds the agent's signer for keyPath. The returned connection
+// backs the signer and must be closed by the caller once it is done signing.
+func agentSigner(keyPath string) (ssh.Signer, io.Closer) {
+	pub, err := readPublicKey(keyPath)
+	if err != nil {
+		return nil, nil
+	}
+	conn, err := dialAgent()
+	if err != nil {
+		return nil, nil
+	}
+
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		conn.Close()
+		return nil, nil
+	}
+	for _, s := range signers {
+		if bytes.Equal(s.PublicKey().Marshal(), pub.Marshal()) {
+			return s, conn
+		}
+	}
+	conn.Close()
+	return nil, nil
+}
@@ -7,48 +7,142 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/config"
 
 	"golang.org/x/crypto/ssh"
 )
 
-func GetDefaultKey() (string, error) {
-	home, _ := os.UserHomeDir()
-	keyDir := filepath.Join(home, ".config", "entropy", "keys")
+const defaultKeyName = "id_ed25519"
+
+// KeysDir holds the default key and any per-node keypairs
+func KeysDir() string {
+	return filepath.Join(config.Dir(), "keys")
+}
+
+// GetDefaultKey returns the shared public key, creating the keypair on first
+// use. created tells the caller to announce the new key; this package prints
+// nothing since the TUI may own the screen.
+func GetDefaultKey() (string, bool, error) {
+	keyDir := KeysDir()
 	if err := os.MkdirAll(keyDir, 0700); err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	privPath := filepath.Join(keyDir, "id_ed25519")
+	privPath := filepath.Join(keyDir, defaultKeyName)
 	pubPath := privPath + ".pub"
 
 	if _, err := os.Stat(privPath); err == nil {
-		return pubPath, nil
+		return pubPath, false, nil
+	}
+
+	if err := writeKeypair(privPath, "", nil); err != nil {
+		return "", false, err
+	}
+	return pubPath, true, nil
+}
+
+// GenerateNodeKey creates a fresh keypair for a single node that is still being
+// provisioned. An empty passphrase leaves the private key unencrypted.
+// Call AdoptNodeKey once the ProviderID is known.
+func GenerateNodeKey(passphrase []byte) (string, error) {
+	return generateNamedKey(fmt.Sprintf("pending-%d_ed25519", time.Now().UnixNano()), passphrase)
+}
+
+// GenerateRotationKey creates a replacement keypair for a running node. The
+// name carries a timestamp so it never collides with the key it replaces.
+func GenerateRotationKey(providerID int64, passphrase []byte) (string, error) {
+	return generateNamedKey(fmt.Sprintf("%d-%d_ed25519", providerID, time.Now().Unix()), passphrase)
+}
+
+func generateNamedKey(name string, passphrase []byte) (string, error) {
+	if err := os.MkdirAll(KeysDir(), 0700); err != nil {
+		return "", err
+	}
+
+	privPath := filepath.Join(KeysDir(), name)
+	if err := writeKeypair(privPath, "entropy-node", passphrase); err != nil {
+		return "", err
+	}
+	return privPath + ".pub", nil
+}
+
+// AdoptNodeKey renames a pending per-node keypair to its ProviderID
+func AdoptNodeKey(pubPath string, providerID int64) (string, error) {
+	privPath := PrivateKeyPath(pubPath)
+	newPriv := NodeKeyPath(providerID)
+
+	if err := os.Rename(privPath, newPriv); err != nil {
+		return pubPath, err
+	}
+	if err := os.Rename(pubPath, newPriv+".pub"); err != nil {
+		return pubPath, err
 	}
+	return newPriv + ".pub", nil
+}
 
+// NodeKeyPath is the private key location for a node-scoped keypair
+func NodeKeyPath(providerID int64) string {
+	return filepath.Join(KeysDir(), fmt.Sprintf("%d_ed25519", providerID))
+}
+
+// IsNodeKey reports whether keyPath is a managed per-node key (never the shared default)
+func IsNodeKey(keyPath string) bool {
+	if keyPath == "" {
+		return false
+	}
+	privPath := PrivateKeyPath(keyPath)
+	if filepath.Dir(privPath) != KeysDir() {
+		return false
+	}
+	return filepath.Base(privPath) != defaultKeyName && strings.HasSuffix(privPath, "_ed25519")
+}
+
+// RemoveNodeKey deletes a per-node keypair. Shared and user-supplied keys are left alone.
+func RemoveNodeKey(keyPath string) error {
+	if !IsNodeKey(keyPath) {
+		return nil
+	}
+	privPath := PrivateKeyPath(keyPath)
+	if err := os.Remove(privPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(privPath + ".pub"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func writeKeypair(privPath, comment string, passphrase []byte) error {
 	pub, priv, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	privBytes, err := ssh.MarshalPrivateKey(priv, "")
+	var privBytes *pem.Block
+	if len(passphrase) > 0 {
+		privBytes, err = ssh.MarshalPrivateKeyWithPassphrase(priv, comment, passphrase)
+	} else {
+		privBytes, err = ssh.MarshalPrivateKey(priv, comment)
+	}
 	if err != nil {
-		return "", err
+		return err
 	}
 	privPem := pem.EncodeToMemory(privBytes)
 
 	if err := os.WriteFile(privPath, privPem, 0600); err != nil {
-		return "", err
+		return err
 	}
 
 	sshPub, err := ssh.NewPublicKey(pub)
 	if err != nil {
-		return "", err
+		return err
 	}
 	pubBytes := ssh.MarshalAuthorizedKey(sshPub)
-	if err := os.WriteFile(pubPath, pubBytes, 0644); err != nil {
-		return "", err
+	if comment != "" {
+		pubBytes = []byte(strings.TrimSpace(string(pubBytes)) + " " + comment + "\n")
 	}
-
-	fmt.Printf("🗝️ Generated new anonymous keypair: %s\n", pubPath)
-	return pubPath, nil
+	return os.WriteFile(privPath+".pub", pubBytes, 0644)
 }
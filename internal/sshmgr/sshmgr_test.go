@@ -0,0 +1,24 @@
+package sshmgr
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetDefaultKeyReportsCreation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	first, created, err := GetDefaultKey()
+	if err != nil || !created {
+		t.Fatalf("first GetDefaultKey = %q, %v, %v; want a new key", first, created, err)
+	}
+	if _, err := os.Stat(first); err != nil {
+		t.Fatal(err)
+	}
+	again, created, err := GetDefaultKey()
+	if err != nil || created || again != first {
+		t.Errorf("second GetDefaultKey = %q, %v, %v; want %q reused", again, created, err, first)
+	}
+}
@@ -0,0 +1,101 @@
+package sshmgr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Sample is one telemetry reading from a node
+type Sample struct {
+	Load1     float64
+	MemTotal  uint64 // bytes
+	MemUsed   uint64
+	DiskTotal uint64
+	DiskUsed  uint64
+	Uptime    time.Duration
+	Ports     []int
+	At        time.Time
+}
+
+// telemetryScript prints one section per metric so a single session
+// covers a whole reading
+const telemetryScript = `cat /proc/loadavg; echo ---
+grep -E '^(MemTotal|MemAvailable):' /proc/meminfo; echo ---
+df -Pk / | tail -n 1; echo ---
+cat /proc/uptime; echo ---
+ss -Hltn 2>/dev/null || netstat -ltn 2>/dev/null | tail -n +3`
+
+// Collect takes a reading over an existing connection. Each call opens a
+// session on c rather than a new connection or local process.
+func Collect(c *ssh.Client) (Sample, error) {
+	out, err := Run(c, telemetryScript, nil)
+	if err != nil {
+		return Sample{}, err
+	}
+	return parseSample(string(out))
+}
+
+func parseSample(out string) (Sample, error) {
+	s := Sample{At: time.Now()}
+	sections := strings.Split(out, "---\n")
+	if len(sections) < 5 {
+		return s, fmt.Errorf("unexpected telemetry output")
+	}
+
+	if f := strings.Fields(sections[0]); len(f) > 0 {
+		s.Load1, _ = strconv.ParseFloat(f[0], 64)
+	}
+
+	var memAvail uint64
+	for _, line := range strings.Split(sections[1], "\n") {
+		f := strings.Fields(line)
+		if len(f) < 2 {
+			continue
+		}
+		kb, _ := strconv.ParseUint(f[1], 10, 64)
+		switch f[0] {
+		case "MemTotal:":
+			s.MemTotal = kb * 1024
+		case "MemAvailable:":
+			memAvail = kb * 1024
+		}
+	}
+	if s.MemTotal >= memAvail {
+		s.MemUsed = s.MemTotal - memAvail
+	}
+
+	// Filesystem 1024-blocks Used Available Capacity Mounted
+	if f := strings.Fields(sections[2]); len(f) >= 4 {
+		total, _ := strconv.ParseUint(f[1], 10, 64)
+		used, _ := strconv.ParseUint(f[2], 10, 64)
+		s.DiskTotal, s.DiskUsed = total*1024, used*1024
+	}
+
+	if f := strings.Fields(sections[3]); len(f) > 0 {
+		secs, _ := strconv.ParseFloat(f[0], 64)
+		s.Uptime = time.Duration(secs) * time.Second
+	}
+
+	// Both ss and netstat put the local address in the fourth column
+	seen := make(map[int]bool)
+	for _, line := range strings.Split(sections[4], "\n") {
+		f := strings.Fields(line)
+		if len(f) < 4 {
+			continue
+		}
+		addr := f[3]
+		port, err := strconv.Atoi(addr[strings.LastIndex(addr, ":")+1:])
+		if err != nil || seen[port] {
+			continue
+		}
+		seen[port] = true
+		s.Ports = append(s.Ports, port)
+	}
+	sort.Ints(s.Ports)
+	return s, nil
+}
@@ -0,0 +1,66 @@
+package sshmgr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sampleHead = `0.42 0.30 0.25 1/123 4567
+---
+MemTotal:        4028340 kB
+MemAvailable:    3028340 kB
+---
+/dev/sda1            41152736   9876543  29162193      26% /
+---
+86400.55 170000.10
+---
+`
+
+func TestParseSample(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports string
+		want  []int
+	}{
+		{"ss", "LISTEN 0      4096   127.0.0.53%lo:53    0.0.0.0:*\nLISTEN 0 128 0.0.0.0:22 0.0.0.0:*\nLISTEN 0 128 [::]:22 [::]:*\nLISTEN 0 511 *:8080 *:*\n", []int{22, 53, 8080}},
+		{"netstat", "tcp        0      0 0.0.0.0:22              0.0.0.0:*               LISTEN\ntcp6       0      0 :::443                  :::*                    LISTEN\n", []int{22, 443}},
+		{"nothing listening", "", nil},
+	}
+	for _, tt := range tests {
+		s, err := parseSample(sampleHead + tt.ports)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if s.Load1 != 0.42 {
+			t.Errorf("%s: Load1 = %v", tt.name, s.Load1)
+		}
+		if s.MemTotal != 4028340*1024 || s.MemUsed != 1000000*1024 {
+			t.Errorf("%s: memory %d/%d", tt.name, s.MemUsed, s.MemTotal)
+		}
+		if s.DiskTotal != 41152736*1024 || s.DiskUsed != 9876543*1024 {
+			t.Errorf("%s: disk %d/%d", tt.name, s.DiskUsed, s.DiskTotal)
+		}
+		if s.Uptime != 24*time.Hour {
+			t.Errorf("%s: uptime %s", tt.name, s.Uptime)
+		}
+		if !reflect.DeepEqual(s.Ports, tt.want) {
+			t.Errorf("%s: ports %v, want %v", tt.name, s.Ports, tt.want)
+		}
+	}
+}
+
+func TestParseSampleMalformed(t *testing.T) {
+	if _, err := parseSample("bash: /proc/loadavg: No such file or directory\n"); err == nil {
+		t.Fatal("expected an error for truncated output")
+	}
+
+	// A MemAvailable above MemTotal must not underflow
+	s, err := parseSample("0.1\n---\nMemTotal: 100 kB\nMemAvailable: 200 kB\n---\n---\n---\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MemUsed != 0 {
+		t.Errorf("MemUsed = %d, want 0", s.MemUsed)
+	}
+}
@@ -0,0 +1,114 @@
+package theme
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette and symbol set shared by the TUI and the CLI tables
+type Theme struct {
+	Name string
+
+	Accent   lipgloss.TerminalColor // headers, borders, selection
+	OnAccent lipgloss.TerminalColor // text drawn on an Accent background
+	Good     lipgloss.TerminalColor // ALIVE, success
+	Warn     lipgloss.TerminalColor // PAUSED, warnings, spend
+	Muted    lipgloss.TerminalColor // labels, help, DEAD
+	Text     lipgloss.TerminalColor // values
+
+	// Symbols are plain ASCII in accessible themes so screen readers and
+	// limited fonts get words instead of pictographs
+	Symbols Symbols
+}
+
+type Symbols struct {
+	OK, Fail, Warn, Marked, Pending string
+}
+
+var unicodeSymbols = Symbols{OK: "✓", Fail: "✗", Warn: "⚠", Marked: "●", Pending: "·"}
+var asciiSymbols = Symbols{OK: "[ok]", Fail: "[fail]", Warn: "[!]", Marked: "*", Pending: "-"}
+
+var themes = map[string]Theme{
+	"default": {
+		Accent:   lipgloss.Color("#FF0000"),
+		OnAccent: lipgloss.Color("#FFFFFF"),
+		Good:     lipgloss.Color("#00FF00"),
+		Warn:     lipgloss.Color("#F1C40F"),
+		Muted:    lipgloss.Color("#444444"),
+		Text:     lipgloss.Color("#FFFFFF"),
+		Symbols:  unicodeSymbols,
+	},
+	"light": {
+		Accent:   lipgloss.Color("#B00020"),
+		OnAccent: lipgloss.Color("#FFFFFF"),
+		Good:     lipgloss.Color("#006B1F"),
+		Warn:     lipgloss.Color("#8A5A00"),
+		Muted:    lipgloss.Color("#5F5F5F"),
+		Text:     lipgloss.Color("#000000"),
+		Symbols:  unicodeSymbols,
+	},
+	// high-contrast sticks to the 16 base ANSI colours so the terminal's own
+	// accessibility palette applies
+	"high-contrast": {
+		Accent:   lipgloss.Color("11"),
+		OnAccent: lipgloss.Color("0"),
+		Good:     lipgloss.Color("10"),
+		Warn:     lipgloss.Color("11"),
+		Muted:    lipgloss.Color("15"),
+		Text:     lipgloss.Color("15"),
+		Symbols:  asciiSymbols,
+	},
+	"mono": {
+		Accent:   lipgloss.NoColor{},
+		OnAccent: lipgloss.NoColor{},
+		Good:     lipgloss.NoColor{},
+		Warn:     lipgloss.NoColor{},
+		Muted:    lipgloss.NoColor{},
+		Text:     lipgloss.NoColor{},
+		Symbols:  asciiSymbols,
+	},
+}
+
+// Names lists the built-in themes
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the named theme. NO_COLOR (https://no-color.org) always wins
+// and unknown names fall back to the default.
+func Load(name string) Theme {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		name = "mono"
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	t, ok := themes[name]
+	if !ok {
+		name = "default"
+		t = themes[name]
+	}
+	t.Name = name
+	return t
+}
+
+// Mono reports whether the theme draws without colour, in which case
+// emphasis has to come from bold, underline and reverse video instead
+func (t Theme) Mono() bool {
+	_, ok := t.Accent.(lipgloss.NoColor)
+	return ok
+}
+
+// Selected is the style for the highlighted row or option
+func (t Theme) Selected() lipgloss.Style {
+	if t.Mono() {
+		return lipgloss.NewStyle().Reverse(true).Bold(true)
+	}
+	return lipgloss.NewStyle().Foreground(t.OnAccent).Background(t.Accent).Bold(true)
+}
@@ -0,0 +1,402 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bulkConcurrency caps parallel paid requests so a large selection doesn't
+// open dozens of x402 negotiations at once
+const bulkConcurrency = 4
+
+type bulkOp int
+
+const (
+	bulkRenew bulkOp = iota
+	bulkDestroy
+)
+
+const (
+	itemQueued = iota
+	itemRunning
+	itemDone
+	itemFailed
+)
+
+type bulkItem struct {
+	vm     db.LocalVM
+	state  int
+	detail string
+}
+
+// bulkEventMsg reports progress of one node; a nil result means it just started
+type bulkEventMsg struct {
+	alias  string
+	result tea.Msg
+}
+
+// bulkEvents carries progress from the worker pool back to the model
+var bulkEvents = make(chan bulkEventMsg, 64)
+
+func waitForBulk() tea.Msg {
+	return <-bulkEvents
+}
+
+// bulkForm holds the confirmation and then the progress of a bulk action
+type bulkForm struct {
+	op       bulkOp
+	items    []bulkItem
+	duration textinput.Model
+	confirm  textinput.Model
+	payIdx   int
+	err      string
+	started  bool
+	finished int
+}
+
+func newBulkForm(op bulkOp, vms []db.LocalVM) bulkForm {
+	f := bulkForm{op: op}
+	for _, vm := range vms {
+		f.items = append(f.items, bulkItem{vm: vm})
+	}
+
+	f.duration = textinput.New()
+	f.duration.Placeholder = "duration (1h, 24h, 168h)"
+	f.duration.SetValue("1h")
+
+	f.confirm = textinput.New()
+	f.confirm.Placeholder = "destroy"
+
+	if op == bulkRenew {
+		f.duration.Focus()
+	} else {
+		f.confirm.Focus()
+	}
+	return f
+}
+
+func (f bulkForm) done() bool {
+	return f.started && f.finished == len(f.items)
+}
+
+// markedVMs resolves the multi-selection against the registry, in table order
+func (m Model) markedVMs() []db.LocalVM {
+	var vms []db.LocalVM
+	for _, r := range m.fleet {
+		if m.marked[r.vm.Alias] {
+			vms = append(vms, r.vm)
+		}
+	}
+	return vms
+}
+
+func runBulk(op bulkOp, vms []db.LocalVM, duration, payMethod string) tea.Cmd {
+	return func() tea.Msg {
+		go func() {
+			sem := make(chan struct{}, bulkConcurrency)
+			var wg sync.WaitGroup
+			for _, vm := range vms {
+				wg.Add(1)
+				go func(vm db.LocalVM) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					bulkEvents <- bulkEventMsg{alias: vm.Alias}
+					var res tea.Msg
+					if op == bulkRenew {
+						res = renewNode(vm, duration, payMethod, 0)
+					} else {
+						res = destroyNode(vm, payMethod)
+					}
+					bulkEvents <- bulkEventMsg{alias: vm.Alias, result: res}
+				}(vm)
+			}
+			wg.Wait()
+		}()
+		return nil
+	}
+}
+
+// openSSHTabs opens one tmux window per node when running inside tmux.
+// Elsewhere the sessions run one after another in this terminal.
+func (m Model) openSSHTabs(vms []db.LocalVM) (Model, tea.Cmd) {
+	if os.Getenv("TMUX") != "" {
+		opened := 0
+		for _, vm := range vms {
+			args := append([]string{"new-window", "-n", vm.Alias, "ssh"}, sshmgr.SSHArgs(vm.IP, vm.SSHKeyPath)...)
+			if exec.Command("tmux", args...).Run() == nil {
+				opened++
+			}
+		}
+		m.status = fmt.Sprintf("OPENED_%d_TMUX_WINDOWS", opened)
+		return m, nil
+	}
+
+	m.sshQueue = vms[1:]
+	m.status = "SSH_" + vms[0].Alias
+	return m, sshSession(vms[0])
+}
+
+func (m Model) startBulk(op bulkOp) (tea.Model, tea.Cmd) {
+	if m.bulk.started && !m.bulk.done() {
+		m.state = stateBulk
+		return m, nil
+	}
+	if m.busy {
+		return m, nil
+	}
+
+	// DEAD nodes are missing from /list, so the orchestrator can neither
+	// extend nor tear them down; they may also belong to another identity
+	var vms []db.LocalVM
+	for _, vm := range m.markedVMs() {
+		if m.rowStatus(vm.Alias) != "DEAD" {
+			vms = append(vms, vm)
+		}
+	}
+	if len(vms) == 0 {
+		m.status = "NO_ELIGIBLE_NODES_MARKED"
+		return m, nil
+	}
+
+	m.bulk = newBulkForm(op, vms)
+	m.state = stateBulk
+	if m.needOptions() {
+		return m, fetchOptions
+	}
+	return m, nil
+}
+
+func (m Model) rowStatus(alias string) string {
+	for _, r := range m.fleet {
+		if r.vm.Alias == alias {
+			return r.status
+		}
+	}
+	return ""
+}
+
+func (m Model) updateBulk(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.bulk.started {
+		switch msg.String() {
+		case "esc", "enter", "q":
+			m.state = stateList
+			if !m.bulk.done() {
+				m.status = "BULK_RUNNING_IN_BACKGROUND"
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.state = stateList
+		return m, nil
+	case "tab", "shift+tab":
+		m.bulk.payIdx = (m.bulk.payIdx + 1) % len(payMethods)
+		return m, nil
+	case "enter":
+		duration := m.bulk.duration.Value()
+		if m.bulk.op == bulkRenew {
+			if _, err := time.ParseDuration(duration); err != nil {
+				m.bulk.err = "invalid duration " + duration
+				return m, nil
+			}
+		} else if strings.TrimSpace(m.bulk.confirm.Value()) != "destroy" {
+			m.bulk.err = "type destroy to confirm"
+			return m, nil
+		}
+
+		vms := make([]db.LocalVM, len(m.bulk.items))
+		for i, it := range m.bulk.items {
+			vms[i] = it.vm
+		}
+		m.bulk.started = true
+		m.bulk.err = ""
+		m.busy = true
+		m.status = fmt.Sprintf("BULK_%s_%d_NODES", m.bulk.verb(), len(vms))
+		return m, tea.Batch(m.spinner.Tick, waitForBulk, runBulk(m.bulk.op, vms, duration, payMethods[m.bulk.payIdx]))
+	}
+
+	var cmd tea.Cmd
+	if m.bulk.op == bulkRenew {
+		m.bulk.duration, cmd = m.bulk.duration.Update(msg)
+	} else {
+		m.bulk.confirm, cmd = m.bulk.confirm.Update(msg)
+	}
+	m.bulk.err = ""
+	return m, cmd
+}
+
+// applyBulkEvent updates the progress list and the fleet; it re-arms the
+// listener until every node has reported a result.
+func (m *Model) applyBulkEvent(msg bulkEventMsg) tea.Cmd {
+	idx := -1
+	for i, it := range m.bulk.items {
+		if it.vm.Alias == msg.alias {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return waitForBulk
+	}
+	item := &m.bulk.items[idx]
+
+	switch res := msg.result.(type) {
+	case nil:
+		item.state = itemRunning
+	case renewResultMsg:
+		m.bulk.finished++
+		if res.err != nil {
+			item.state, item.detail = itemFailed, res.err.Error()
+		} else {
+			item.state, item.detail = itemDone, "expires "+res.expiry.Local().Format(time.RFC1123)
+			m.applyRenewal(res)
+		}
+	case destroyResultMsg:
+		m.bulk.finished++
+		switch {
+		case res.err != nil && !errors.Is(res.err, fleet.ErrLocalCleanup):
+			item.state, item.detail = itemFailed, res.err.Error()
+		case res.err != nil:
+			item.state, item.detail = itemDone, res.err.Error()
+			m.forgetRow(res.alias)
+		default:
+			item.state, item.detail = itemDone, "destroyed"
+			m.forgetRow(res.alias)
+		}
+	}
+
+	if !m.bulk.done() {
+		return waitForBulk
+	}
+	m.busy = false
+	failed := 0
+	for _, it := range m.bulk.items {
+		if it.state == itemFailed {
+			failed++
+		}
+	}
+	m.status = fmt.Sprintf("BULK_%s_DONE (%d ok, %d failed)", m.bulk.verb(), len(m.bulk.items)-failed, failed)
+	return nil
+}
+
+func (f bulkForm) verb() string {
+	if f.op == bulkRenew {
+		return "RENEW"
+	}
+	return "DESTROY"
+}
+
+// bulkCostView totals the renewal quote, or for a destroy the lease value
+// that is given up, across every node in the batch
+func (m Model) bulkCostView() string {
+	if m.options == nil {
+		if m.optionsErr != nil {
+			return helpStyle.Render("unavailable: " + m.optionsErr.Error())
+		}
+		return helpStyle.Render("fetching manifest...")
+	}
+
+	total, unpriced := 0.0, 0
+	for _, it := range m.bulk.items {
+		var cost float64
+		var err error
+		if m.bulk.op == bulkRenew {
+			cost, err = m.options.Quote(it.vm.Tier, it.vm.Region, m.bulk.duration.Value())
+		} else if remaining := time.Until(it.vm.ExpiresAt); remaining > 0 {
+			cost, err = m.options.Quote(it.vm.Tier, it.vm.Region, remaining.String())
+		}
+		if err != nil {
+			unpriced++
+			continue
+		}
+		total += cost
+	}
+
+	label := "~$%.4f USD"
+	if m.bulk.op == bulkDestroy {
+		label = "~$%.4f USD of remaining lease forfeited"
+	}
+	out := lipgloss.NewStyle().Foreground(bright).Render(fmt.Sprintf(label, total))
+	if unpriced > 0 {
+		out += lipgloss.NewStyle().Foreground(warn).Render(fmt.Sprintf(" (%d node(s) not priced)", unpriced))
+	}
+	return out
+}
+
+func (m Model) bulkView() string {
+	title := "[ BULK_RENEW // %d NODES ]"
+	if m.bulk.op == bulkDestroy {
+		title = "[ BULK_DESTROY // %d NODES ]"
+	}
+	rows := []string{
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("\n" + fmt.Sprintf(title, len(m.bulk.items))),
+		"",
+	}
+
+	for _, it := range m.bulk.items {
+		var mark string
+		switch it.state {
+		case itemQueued:
+			mark = helpStyle.Render(sym.Pending)
+		case itemRunning:
+			mark = m.spinner.View()
+		case itemDone:
+			mark = lipgloss.NewStyle().Foreground(good).Render(sym.OK)
+		case itemFailed:
+			mark = lipgloss.NewStyle().Foreground(accent).Render(sym.Fail)
+		}
+		line := fmt.Sprintf("%s %-25s %-8s %s", mark, it.vm.Alias, it.vm.Region, helpStyle.Render(it.detail))
+		rows = append(rows, line)
+	}
+	rows = append(rows, "")
+
+	if m.bulk.started {
+		rows = append(rows, helpStyle.Render(fmt.Sprintf("%d/%d finished • esc: back to fleet", m.bulk.finished, len(m.bulk.items))))
+		return lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	}
+
+	pay := ""
+	for i, p := range payMethods {
+		label := " " + p + " "
+		if i == m.bulk.payIdx {
+			pay += selectedStyle.Render(label)
+		} else {
+			pay += helpStyle.Render(label)
+		}
+		pay += " "
+	}
+
+	if m.bulk.op == bulkRenew {
+		rows = append(rows, "Duration: "+m.bulk.duration.View())
+	} else {
+		rows = append(rows,
+			lipgloss.NewStyle().Foreground(warn).Render("This permanently deletes every node listed above."),
+			"Type destroy to confirm: "+m.bulk.confirm.View(),
+		)
+	}
+	rows = append(rows,
+		"Payment:  "+pay,
+		"Total:    "+m.bulkCostView(),
+	)
+	if m.bulk.err != "" {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(accent).Render(sym.Fail+" "+m.bulk.err))
+	}
+	rows = append(rows, "", helpStyle.Render(fmt.Sprintf("enter: confirm • tab: payment • esc: cancel • %d at a time", bulkConcurrency)))
+	return lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
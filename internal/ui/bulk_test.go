@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/x402-Systems/entropy/internal/db"
+)
+
+func TestStartBulkWaitsForBusyAction(t *testing.T) {
+	m := Model{state: stateList, busy: true, marked: map[string]bool{"web-1": true}}
+	for _, op := range []bulkOp{bulkRenew, bulkDestroy} {
+		next, cmd := m.startBulk(op)
+		got := next.(Model)
+		if got.state != stateList || got.bulk.started || cmd != nil {
+			t.Errorf("op %v started while another action was running", op)
+		}
+	}
+}
+
+func TestStartBulkSkipsDeadNodes(t *testing.T) {
+	m := Model{state: stateList, options: testOptions(), fleet: []fleetRow{
+		{vm: db.LocalVM{Alias: "web-1"}, status: "ALIVE"},
+		{vm: db.LocalVM{Alias: "web-2"}, status: "PAUSED"},
+		{vm: db.LocalVM{Alias: "gone-1"}, status: "DEAD"},
+	}, marked: map[string]bool{"web-1": true, "web-2": true, "gone-1": true}}
+	for _, op := range []bulkOp{bulkRenew, bulkDestroy} {
+		next, _ := m.startBulk(op)
+		got := next.(Model)
+		var aliases []string
+		for _, it := range got.bulk.items {
+			aliases = append(aliases, it.vm.Alias)
+		}
+		if len(aliases) != 2 || aliases[0] != "web-1" || aliases[1] != "web-2" {
+			t.Errorf("op %v queued %v, want [web-1 web-2]", op, aliases)
+		}
+	}
+
+	m.marked = map[string]bool{"gone-1": true}
+	next, _ := m.startBulk(bulkDestroy)
+	if got := next.(Model); got.state != stateList || got.status != "NO_ELIGIBLE_NODES_MARKED" {
+		t.Errorf("state %v status %q with only a DEAD node marked", got.state, got.status)
+	}
+}
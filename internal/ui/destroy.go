@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type destroyResultMsg struct {
+	alias string
+	err   error
+}
+
+// destroyForm holds the state of the `d` confirmation modal
+type destroyForm struct {
+	vm      db.LocalVM
+	confirm textinput.Model
+	payIdx  int
+	err     string
+}
+
+func newDestroyForm(vm db.LocalVM) destroyForm {
+	c := textinput.New()
+	c.Placeholder = vm.Alias
+	c.Focus()
+	return destroyForm{vm: vm, confirm: c}
+}
+
+func destroyVM(vm db.LocalVM, payMethod string) tea.Cmd {
+	return func() tea.Msg {
+		return destroyNode(vm, payMethod)
+	}
+}
+
+func destroyNode(vm db.LocalVM, payMethod string) destroyResultMsg {
+	client, err := newClient(payMethod)
+	if err != nil {
+		return destroyResultMsg{alias: vm.Alias, err: err}
+	}
+	err = fleet.Destroy(context.Background(), client, vm)
+	return destroyResultMsg{alias: vm.Alias, err: err}
+}
+
+func (m Model) updateDestroy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = stateList
+		return m, nil
+	case "tab", "shift+tab":
+		m.destroy.payIdx = (m.destroy.payIdx + 1) % len(payMethods)
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(m.destroy.confirm.Value()) != m.destroy.vm.Alias {
+			m.destroy.err = "alias does not match"
+			return m, nil
+		}
+		pay := payMethods[m.destroy.payIdx]
+		m.state = stateList
+		m.busy = true
+		m.status = fmt.Sprintf("DESTROYING_%s_(%s)", m.destroy.vm.Alias, pay)
+		return m, tea.Batch(m.spinner.Tick, destroyVM(m.destroy.vm, pay))
+	}
+
+	var cmd tea.Cmd
+	m.destroy.confirm, cmd = m.destroy.confirm.Update(msg)
+	m.destroy.err = ""
+	return m, cmd
+}
+
+// applyDestroy drops the row only once the orchestrator confirmed the teardown
+func (m *Model) applyDestroy(msg destroyResultMsg) {
+	m.busy = false
+	if msg.err != nil && !errors.Is(msg.err, fleet.ErrLocalCleanup) {
+		m.status = "DESTROY_FAILED: " + msg.err.Error()
+		return
+	}
+
+	if msg.err != nil {
+		m.status = "WARNING: " + msg.err.Error()
+	} else {
+		m.status = "DESTROYED_" + msg.alias
+	}
+
+	m.forgetRow(msg.alias)
+}
+
+// forgetRow drops a destroyed node from the table and the multi-selection
+func (m *Model) forgetRow(alias string) {
+	delete(m.marked, alias)
+	kept := make([]fleetRow, 0, len(m.fleet))
+	for _, r := range m.fleet {
+		if r.vm.Alias != alias {
+			kept = append(kept, r)
+		}
+	}
+	m.fleet = kept
+	m.refreshTable()
+}
+
+func (m Model) destroyView() string {
+	pay := ""
+	for i, p := range payMethods {
+		label := " " + p + " "
+		if i == m.destroy.payIdx {
+			pay += selectedStyle.Render(label)
+		} else {
+			pay += helpStyle.Render(label)
+		}
+		pay += " "
+	}
+
+	rows := []string{
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("\n[ DESTROY_NODE // " + m.destroy.vm.Alias + " ]"),
+		"",
+		lipgloss.NewStyle().Foreground(warn).Render("This permanently deletes the VM and its disk. Remaining lease time is not refunded."),
+		helpStyle.Render(fmt.Sprintf("Server: %s  IP: %s  Region: %s", m.destroy.vm.ServerName, m.destroy.vm.IP, m.destroy.vm.Region)),
+		"",
+		"Type the alias to confirm:",
+		m.destroy.confirm.View(),
+		"Payment:  " + pay,
+	}
+	if m.destroy.err != "" {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(accent).Render(sym.Fail+" "+m.destroy.err))
+	}
+	rows = append(rows, "", helpStyle.Render("enter: destroy • tab: payment • esc: cancel"))
+	return lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type sshDoneMsg struct {
+	alias string
+	err   error
+}
+
+type commandResultMsg struct {
+	command string
+	output  string
+	err     error
+}
+
+// commandForm holds the state of the `x` run-command prompt
+type commandForm struct {
+	vm      db.LocalVM
+	input   textinput.Model
+	output  viewport.Model
+	running bool
+}
+
+func newCommandForm(vm db.LocalVM, width, height int) commandForm {
+	in := textinput.New()
+	in.Placeholder = "command (e.g. uptime, df -h, journalctl -n 50)"
+	in.Focus()
+
+	vp := viewport.New(max(width-8, 20), max(height-16, 5))
+	vp.SetContent(helpStyle.Render("Output appears here."))
+	return commandForm{vm: vm, input: in, output: vp}
+}
+
+// sshSession hands the terminal to ssh and returns to the dashboard when it exits
+func sshSession(vm db.LocalVM) tea.Cmd {
+	c := exec.Command("ssh", sshmgr.SSHArgs(vm.IP, vm.SSHKeyPath)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return sshDoneMsg{alias: vm.Alias, err: err}
+	})
+}
+
+// runCommand executes a one-shot command without a TTY. BatchMode stops ssh
+// from prompting underneath the dashboard; encrypted keys must be in ssh-agent.
+func runCommand(vm db.LocalVM, command string) tea.Cmd {
+	return func() tea.Msg {
+		args := append([]string{"-o", "BatchMode=yes"}, sshmgr.SSHArgs(vm.IP, vm.SSHKeyPath)...)
+		out, err := exec.Command("ssh", append(args, command)...).CombinedOutput()
+		if err != nil {
+			slog.Warn("remote command failed", "alias", vm.Alias, "err", err)
+		} else {
+			slog.Info("remote command finished", "alias", vm.Alias)
+		}
+		return commandResultMsg{command: command, output: string(out), err: err}
+	}
+}
+
+func (m Model) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "esc":
+		m.state = stateList
+		return m, nil
+	case "up", "down", "pgup", "pgdown":
+		m.command.output, cmd = m.command.output.Update(msg)
+		return m, cmd
+	case "enter":
+		command := strings.TrimSpace(m.command.input.Value())
+		if command == "" || m.command.running {
+			return m, nil
+		}
+		m.command.running = true
+		m.busy = true
+		m.status = "EXEC_" + m.command.vm.Alias
+		return m, tea.Batch(m.spinner.Tick, runCommand(m.command.vm, command))
+	}
+
+	m.command.input, cmd = m.command.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) applyCommandResult(msg commandResultMsg) {
+	m.command.running = false
+	m.busy = false
+
+	header := lipgloss.NewStyle().Foreground(accent).Render("$ " + msg.command)
+	body := strings.TrimRight(msg.output, "\n")
+	if msg.err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(msg.err, &exitErr) {
+			body += "\n" + lipgloss.NewStyle().Foreground(warn).Render(fmt.Sprintf("[exit %d]", exitErr.ExitCode()))
+		} else {
+			body += "\n" + lipgloss.NewStyle().Foreground(accent).Render(sym.Fail+" "+msg.err.Error())
+		}
+		m.status = "EXEC_FAILED"
+	} else {
+		m.status = "EXEC_OK"
+	}
+
+	m.command.output.SetContent(header + "\n" + body)
+	m.command.output.GotoTop()
+	m.command.input.SetValue("")
+}
+
+func (m Model) commandView() string {
+	running := ""
+	if m.command.running {
+		running = m.spinner.View() + " running..."
+	}
+	form := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("\n[ RUN_COMMAND // "+m.command.vm.Alias+" ]"),
+		"",
+		"Command: "+m.command.input.View()+" "+running,
+		"",
+		lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(muted).Render(m.command.output.View()),
+		helpStyle.Render(fmt.Sprintf("enter: run • ↑/↓/pgup/pgdn: scroll (%3.f%%) • esc: back", m.command.output.ScrollPercent()*100)),
+	)
+	return lipgloss.NewStyle().Padding(1, 4).Render(form)
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fleetRow is one node as last seen by a sync; table rows are derived from it
+type fleetRow struct {
+	vm     db.LocalVM
+	status string
+	// expires is the orchestrator's expiry, or the local one for DEAD nodes
+	expires time.Time
+	// cost is nil until a payment has been recorded for the node
+	cost *fleet.Cost
+}
+
+func (r fleetRow) ttl() string {
+	switch r.status {
+	case "DEAD":
+		return "0s"
+	case "PAUSED":
+		return "GRACE PERIOD"
+	}
+	if remaining := time.Until(r.expires).Round(time.Second); remaining > 0 {
+		return remaining.String()
+	}
+	return "0s"
+}
+
+func (r fleetRow) expired() bool {
+	return !r.expires.After(time.Now())
+}
+
+type sortKey int
+
+const (
+	sortTTL sortKey = iota
+	sortRegion
+	sortCreated
+	sortKeyCount
+)
+
+var sortNames = [sortKeyCount]string{"TTL", "REGION", "CREATED"}
+
+// listView holds the filter, visibility and ordering of the fleet table
+type listView struct {
+	filter      textinput.Model
+	filtering   bool
+	hideDead    bool
+	hideExpired bool
+	sortBy      sortKey
+	reverse     bool
+}
+
+func newListView() listView {
+	f := textinput.New()
+	f.Prompt = "/"
+	f.Placeholder = "alias, ip, region or tier"
+	return listView{filter: f}
+}
+
+func (v listView) matches(r fleetRow) bool {
+	if v.hideDead && r.status == "DEAD" {
+		return false
+	}
+	if v.hideExpired && r.expired() {
+		return false
+	}
+	q := strings.ToLower(strings.TrimSpace(v.filter.Value()))
+	if q == "" {
+		return true
+	}
+	for _, field := range []string{r.vm.Alias, r.vm.IP, r.vm.Region, r.vm.Tier} {
+		if strings.Contains(strings.ToLower(field), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// less orders rows by the active key; the default puts the longest lease first
+func (v listView) less(a, b fleetRow) bool {
+	if v.reverse {
+		a, b = b, a
+	}
+	switch v.sortBy {
+	case sortRegion:
+		if a.vm.Region != b.vm.Region {
+			return a.vm.Region < b.vm.Region
+		}
+		return a.vm.Alias < b.vm.Alias
+	case sortCreated:
+		return a.vm.CreatedAt.After(b.vm.CreatedAt)
+	}
+	return a.expires.After(b.expires)
+}
+
+// refreshTable rebuilds the visible rows while keeping the cursor on the same node
+func (m *Model) refreshTable() {
+	selected := ""
+	if curr := m.table.SelectedRow(); len(curr) > 0 {
+		selected = curr[colAlias]
+	}
+
+	visible := make([]fleetRow, 0, len(m.fleet))
+	for _, r := range m.fleet {
+		if m.list.matches(r) {
+			visible = append(visible, r)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool { return m.list.less(visible[i], visible[j]) })
+
+	rows := make([]table.Row, 0, len(visible))
+	cursor := m.table.Cursor()
+	for i, r := range visible {
+		mark := ""
+		if m.marked[r.vm.Alias] {
+			mark = sym.Marked
+		}
+		renewals := "0"
+		if r.cost != nil {
+			renewals = fmt.Sprint(r.cost.Renewals)
+		}
+		rows = append(rows, table.Row{mark, r.vm.Alias, r.status, r.vm.IP, r.ttl(), r.vm.Region, r.cost.String(), renewals, fleet.Age(r.vm)})
+		if r.vm.Alias == selected {
+			cursor = i
+		}
+	}
+	m.table.SetRows(rows)
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	m.table.SetCursor(max(cursor, 0))
+}
+
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.list.filter.SetValue("")
+		fallthrough
+	case "enter":
+		m.list.filtering = false
+		m.list.filter.Blur()
+		m.refreshTable()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list.filter, cmd = m.list.filter.Update(msg)
+	m.refreshTable()
+	return m, cmd
+}
+
+func (m Model) listStatusView() string {
+	parts := []string{}
+	if m.list.filtering {
+		parts = append(parts, m.list.filter.View())
+	} else if q := m.list.filter.Value(); q != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(bright).Render("/"+q))
+	}
+
+	order := sortNames[m.list.sortBy]
+	if m.list.reverse {
+		order += " ↑"
+	} else {
+		order += " ↓"
+	}
+	parts = append(parts, helpStyle.Render("SORT: "+order))
+	if m.list.hideDead {
+		parts = append(parts, helpStyle.Render("HIDING DEAD"))
+	}
+	if m.list.hideExpired {
+		parts = append(parts, helpStyle.Render("HIDING EXPIRED"))
+	}
+	parts = append(parts, helpStyle.Render(fmt.Sprintf("%d/%d NODES", len(m.table.Rows()), len(m.fleet))))
+	if len(m.marked) > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(warn).Render(fmt.Sprintf("%d MARKED", len(m.marked))))
+	}
+	return strings.Join(parts, helpStyle.Render("  •  "))
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds the dashboard bindings. Forms keep fixed enter/esc/tab keys.
+type keyMap struct {
+	New         key.Binding
+	Renew       key.Binding
+	Destroy     key.Binding
+	SSH         key.Binding
+	Exec        key.Binding
+	Sync        key.Binding
+	AutoSync    key.Binding
+	Mark        key.Binding
+	MarkAll     key.Binding
+	ClearMarks  key.Binding
+	Filter      key.Binding
+	Sort        key.Binding
+	Reverse     key.Binding
+	HideDead    key.Binding
+	HideExpired key.Binding
+	Telemetry   key.Binding
+	Log         key.Binding
+	LogUp       key.Binding
+	LogDown     key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	b := func(help string, keys ...string) key.Binding {
+		return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyLabel(keys[0]), help))
+	}
+	return keyMap{
+		New:         b("new node", "n"),
+		Renew:       b("renew", "r"),
+		Destroy:     b("destroy", "d"),
+		SSH:         b("ssh", "s"),
+		Exec:        b("run command", "x"),
+		Sync:        b("sync now", "ctrl+r"),
+		AutoSync:    b("auto-sync", "a"),
+		Mark:        b("mark", " "),
+		MarkAll:     b("mark all", "*"),
+		ClearMarks:  b("clear marks", "esc"),
+		Filter:      b("filter", "/"),
+		Sort:        b("sort column", "o"),
+		Reverse:     b("reverse sort", "O"),
+		HideDead:    b("hide dead", "h"),
+		HideExpired: b("hide expired", "e"),
+		Telemetry:   b("telemetry", "t"),
+		Log:         b("event log", "l"),
+		LogUp:       b("log up", "ctrl+u"),
+		LogDown:     b("log down", "ctrl+d"),
+		Help:        b("help", "?"),
+		Quit:        b("quit", "q"),
+	}
+}
+
+// actions names each binding as it appears in keys.json
+func (k *keyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"new": &k.New, "renew": &k.Renew, "destroy": &k.Destroy,
+		"ssh": &k.SSH, "exec": &k.Exec, "sync": &k.Sync, "auto_sync": &k.AutoSync,
+		"mark": &k.Mark, "mark_all": &k.MarkAll, "clear_marks": &k.ClearMarks,
+		"filter": &k.Filter, "sort": &k.Sort, "reverse": &k.Reverse,
+		"hide_dead": &k.HideDead, "hide_expired": &k.HideExpired,
+		"telemetry": &k.Telemetry, "log": &k.Log, "log_up": &k.LogUp, "log_down": &k.LogDown,
+		"help": &k.Help, "quit": &k.Quit,
+	}
+}
+
+// withOverrides rebinds actions from keys.json and returns any unknown names
+func (k keyMap) withOverrides(overrides map[string][]string) (keyMap, []string) {
+	actions := k.actions()
+	var unknown []string
+	for name, keys := range overrides {
+		b, ok := actions[name]
+		if !ok || len(keys) == 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keyLabel(keys[0]), b.Help().Desc)
+	}
+	sort.Strings(unknown)
+	return k, unknown
+}
+
+func keyLabel(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.New, k.Renew, k.SSH, k.Destroy, k.Filter, k.Help, k.Quit}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.New, k.Renew, k.Destroy, k.SSH, k.Exec},
+		{k.Mark, k.MarkAll, k.ClearMarks, k.Filter, k.Sort, k.Reverse, k.HideDead, k.HideExpired},
+		{k.Sync, k.AutoSync, k.Telemetry, k.Log, k.LogUp, k.LogDown, k.Help, k.Quit},
+	}
+}
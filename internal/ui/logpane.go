@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/x402-Systems/entropy/internal/eventlog"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	logScrollback = 500
+	logPaneHeight = 8
+)
+
+type logMsg eventlog.Entry
+
+func waitForLog() tea.Msg {
+	return logMsg(<-eventlog.Entries())
+}
+
+// logPane is the `l` panel showing operations, x402 negotiations,
+// settlements and errors as they are logged
+type logPane struct {
+	visible bool
+	entries []eventlog.Entry
+	view    viewport.Model
+}
+
+func newLogPane() logPane {
+	return logPane{view: viewport.New(80, logPaneHeight)}
+}
+
+// append keeps following the tail unless the user has scrolled back
+func (p *logPane) append(e eventlog.Entry) {
+	follow := p.view.AtBottom()
+	p.entries = append(p.entries, e)
+	if len(p.entries) > logScrollback {
+		p.entries = p.entries[len(p.entries)-logScrollback:]
+	}
+	p.render()
+	if follow {
+		p.view.GotoBottom()
+	}
+}
+
+func (p *logPane) render() {
+	lines := make([]string, len(p.entries))
+	for i, e := range p.entries {
+		lines[i] = formatEntry(e)
+	}
+	p.view.SetContent(strings.Join(lines, "\n"))
+}
+
+func formatEntry(e eventlog.Entry) string {
+	level := lipgloss.NewStyle().Foreground(muted)
+	switch {
+	case e.Level >= slog.LevelError:
+		level = lipgloss.NewStyle().Foreground(accent).Bold(true)
+	case e.Level >= slog.LevelWarn:
+		level = lipgloss.NewStyle().Foreground(warn)
+	case strings.HasPrefix(e.Message, "payment settled"):
+		level = lipgloss.NewStyle().Foreground(good)
+	}
+	return helpStyle.Render(e.Time.Format("15:04:05")) + " " +
+		level.Render(fmt.Sprintf("%-5s", e.Level.String())) + " " +
+		lipgloss.NewStyle().Foreground(bright).Render(e.Message) + " " +
+		helpStyle.Render(e.Attrs)
+}
+
+// layout sizes the table and the log pane to the terminal
+func (m *Model) layout() {
+	tableHeight := m.height - 16
+	if m.logs.visible {
+		tableHeight -= logPaneHeight + 2
+	}
+	m.table.SetHeight(max(tableHeight, 3))
+	m.logs.view.Width = max(m.width-4, 20)
+	m.logs.view.Height = logPaneHeight
+}
+
+func (m Model) logView() string {
+	title := lipgloss.NewStyle().Foreground(accent).Bold(true).Render("[ EVENT_LOG ]") +
+		helpStyle.Render("  "+eventlog.Path()+" • "+m.keys.LogUp.Help().Key+"/"+m.keys.LogDown.Help().Key+": scroll • "+m.keys.Log.Help().Key+": hide")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.logs.view.View())
+}
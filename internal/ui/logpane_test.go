@@ -0,0 +1,14 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogViewHintFollowsKeymap(t *testing.T) {
+	keys, _ := defaultKeyMap().withOverrides(map[string][]string{"log": {"L"}, "log_up": {"pgup"}, "log_down": {"pgdown"}})
+	m := Model{keys: keys, logs: newLogPane()}
+	if got := m.logView(); !strings.Contains(got, "pgup/pgdown: scroll") || !strings.Contains(got, "L: hide") {
+		t.Errorf("log pane hint ignores the keymap:\n%s", got)
+	}
+}
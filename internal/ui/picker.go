@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isPicker reports whether a form field is driven by the /options manifest
+// instead of free text. Without a manifest the fields stay editable.
+func (m Model) isPicker(field int) bool {
+	return len(m.pickerItems(field)) > 0
+}
+
+func (m Model) pickerItems(field int) []string {
+	if m.options == nil {
+		return nil
+	}
+	switch field {
+	case fieldTier:
+		return m.options.TierNames()
+	case fieldRegion:
+		if t, ok := m.options.Tiers[m.inputs[fieldTier].Value()]; ok {
+			return t.RegionNames()
+		}
+	case fieldDistro:
+		return m.options.Distros
+	}
+	return nil
+}
+
+// needOptions reports whether the manifest should be (re)fetched: it was never
+// loaded, or only the offline cache was available last time
+func (m Model) needOptions() bool {
+	return m.options == nil || !m.options.FetchedAt.IsZero()
+}
+
+// cyclePicker moves a picker selection and keeps the region valid for the tier
+func (m *Model) cyclePicker(field, delta int) {
+	items := m.pickerItems(field)
+	if len(items) == 0 {
+		return
+	}
+	idx := indexOf(items, m.inputs[field].Value())
+	idx = (idx + delta + len(items)) % len(items)
+	m.inputs[field].SetValue(items[idx])
+	m.normalizePickers()
+}
+
+// normalizePickers snaps every picker onto a value the manifest offers
+func (m *Model) normalizePickers() {
+	for _, field := range []int{fieldTier, fieldRegion, fieldDistro} {
+		items := m.pickerItems(field)
+		if len(items) > 0 && indexOf(items, m.inputs[field].Value()) < 0 {
+			m.inputs[field].SetValue(items[0])
+		}
+	}
+}
+
+func indexOf(items []string, v string) int {
+	for i, it := range items {
+		if it == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) pickerLabel(field int, item string) string {
+	switch field {
+	case fieldTier:
+		t := m.options.Tiers[item]
+		label := fmt.Sprintf("%-14s CPU %-4v RAM %-6v DISK %-6v", item, t.CPU, t.RAM, t.Disk)
+		if r, ok := t.Regions[m.inputs[fieldRegion].Value()]; ok {
+			label += fmt.Sprintf(" $%.4f/h", float64(r.HourlyCost))
+		}
+		return label
+	case fieldRegion:
+		t := m.options.Tiers[m.inputs[fieldTier].Value()]
+		return fmt.Sprintf("%-14s $%.4f/h", item, float64(t.Regions[item].HourlyCost))
+	}
+	return item
+}
+
+func (m Model) pickerView(field int) string {
+	value := m.inputs[field].Value()
+	if field != m.focusIdx {
+		return lipgloss.NewStyle().Foreground(bright).Render(m.pickerLabel(field, value))
+	}
+
+	lines := make([]string, 0, len(m.pickerItems(field)))
+	for _, item := range m.pickerItems(field) {
+		if item == value {
+			lines = append(lines, selectedStyle.Render("> "+m.pickerLabel(field, item)))
+		} else {
+			lines = append(lines, helpStyle.Render("  "+m.pickerLabel(field, item)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// estimateView is the live total for the current tier, region and duration
+func (m Model) estimateView() string {
+	if m.options == nil {
+		if m.optionsErr != nil {
+			return helpStyle.Render("unavailable: " + m.optionsErr.Error())
+		}
+		return helpStyle.Render("fetching manifest...")
+	}
+	tier, region := m.inputs[fieldTier].Value(), m.inputs[fieldRegion].Value()
+	duration := m.inputs[fieldDuration].Value()
+	cost, err := m.options.Quote(tier, region, duration)
+	if err != nil {
+		return helpStyle.Render("unavailable: " + err.Error())
+	}
+	hourly := m.options.Tiers[tier].Regions[region].HourlyCost
+	est := lipgloss.NewStyle().Foreground(bright).Render(fmt.Sprintf("~$%.4f USD", cost))
+	return est + helpStyle.Render(fmt.Sprintf(" (%s at $%.4f/h)", duration, float64(hourly)))
+}
+
+func (m Model) manifestNote() string {
+	if m.options == nil || m.options.FetchedAt.IsZero() {
+		return ""
+	}
+	age := time.Since(m.options.FetchedAt).Round(time.Minute)
+	return lipgloss.NewStyle().Foreground(warn).Render(fmt.Sprintf("%s Orchestrator unreachable; showing manifest cached %s ago", sym.Warn, age))
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/fleet"
+)
+
+func testOptions() *api.Options {
+	return &api.Options{
+		Tiers: map[string]api.Tier{
+			"standard": {Regions: map[string]api.RegionPrice{"fsn1": {HourlyCost: 0.02}, "ash": {HourlyCost: 0.03}}},
+			"tiny":     {Regions: map[string]api.RegionPrice{"hel1": {HourlyCost: 0.005}}},
+		},
+		Distros: []string{"debian-12", "ubuntu-24.04"},
+	}
+}
+
+func TestNormalizePickers(t *testing.T) {
+	m := Model{options: testOptions(), inputs: newProvisionInputs()}
+	m.normalizePickers()
+
+	want := map[int]string{fieldTier: "tiny", fieldRegion: "hel1", fieldDistro: "ubuntu-24.04"}
+	for field, v := range want {
+		if got := m.inputs[field].Value(); got != v {
+			t.Errorf("field %d = %q, want %q", field, got, v)
+		}
+	}
+
+	m.cyclePicker(fieldTier, 1)
+	if tier, region := m.inputs[fieldTier].Value(), m.inputs[fieldRegion].Value(); tier != "standard" || region != "ash" {
+		t.Errorf("after cycling: tier %q region %q, want standard ash", tier, region)
+	}
+}
+
+func TestNeedOptions(t *testing.T) {
+	cached := testOptions()
+	cached.FetchedAt = time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		opts *api.Options
+		want bool
+	}{
+		{"never loaded", nil, true},
+		{"offline cache", cached, true},
+		{"live", testOptions(), false},
+	}
+	for _, tt := range tests {
+		if got := (Model{options: tt.opts}).needOptions(); got != tt.want {
+			t.Errorf("%s: needOptions = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFailedRefreshKeepsCachedOptions(t *testing.T) {
+	cached := testOptions()
+	cached.FetchedAt = time.Now().Add(-time.Hour)
+	m := Model{options: cached, inputs: newProvisionInputs()}
+
+	next, _ := m.Update(optionsMsg{err: errors.New("unreachable")})
+	if got := next.(Model).options; got != cached {
+		t.Fatal("a failed refresh dropped the cached manifest")
+	}
+
+	live := testOptions()
+	next, _ = next.(Model).Update(optionsMsg{opts: live})
+	if got := next.(Model).options; got != live {
+		t.Fatal("a successful refresh did not replace the cached manifest")
+	}
+}
+
+func TestProvisionResetNormalizesPickers(t *testing.T) {
+	m := Model{state: stateProvisioning, busy: true, options: testOptions(), inputs: newProvisionInputs()}
+	next, _ := m.Update(provisionResultMsg{res: &fleet.ProvisionResult{}})
+	got := next.(Model)
+	if got.inputs[fieldTier].Value() != "tiny" || got.inputs[fieldRegion].Value() != "hel1" {
+		t.Fatalf("form reset to tier %q region %q, not a manifest value",
+			got.inputs[fieldTier].Value(), got.inputs[fieldRegion].Value())
+	}
+}
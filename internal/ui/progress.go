@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type progressMsg struct {
+	gen int
+	api.Progress
+}
+
+// progressEvents carries x402 stages from the foreground provision or renew
+var progressEvents = make(chan progressMsg, 32)
+
+func waitForProgress() tea.Msg {
+	return <-progressEvents
+}
+
+// reportProgress forwards client stages tagged with gen. Zero leaves the
+// client untracked, which is how bulk jobs keep out of the panel.
+func reportProgress(client *api.Client, gen int) {
+	if gen == 0 {
+		return
+	}
+	client.OnProgress = func(p api.Progress) {
+		select {
+		case progressEvents <- progressMsg{gen: gen, Progress: p}:
+		default:
+		}
+	}
+}
+
+// paymentProgress is the stage list for the paid operation in flight. gen
+// increases per operation so late stages from an earlier one are dropped.
+type paymentProgress struct {
+	gen     int
+	title   string
+	started time.Time
+	stages  []api.Progress
+}
+
+func (m *Model) startProgress(title string) int {
+	m.progress = paymentProgress{gen: m.progress.gen + 1, title: title, started: time.Now()}
+	return m.progress.gen
+}
+
+func (m *Model) applyProgress(msg progressMsg) {
+	if msg.gen == m.progress.gen {
+		m.progress.stages = append(m.progress.stages, msg.Progress)
+	}
+}
+
+func (m *Model) finishProgress() {
+	m.progress.title = ""
+	m.progress.stages = nil
+}
+
+// shorten keeps transaction hashes from wrapping the details panel
+func shorten(s string) string {
+	if len(s) <= 24 || strings.Contains(s, " ") {
+		return s
+	}
+	return s[:10] + "…" + s[len(s)-8:]
+}
+
+func (m Model) progressView() string {
+	p := m.progress
+	rows := []string{
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("[ X402_PAYMENT ]"),
+		lipgloss.NewStyle().Foreground(bright).Render(p.title) +
+			helpStyle.Render(fmt.Sprintf("  %s", time.Since(p.started).Round(time.Second))),
+		"",
+	}
+	for _, s := range p.stages {
+		line := lipgloss.NewStyle().Foreground(good).Render(sym.OK) + " " + s.Stage.String()
+		if s.Detail != "" {
+			line += helpStyle.Render(" " + shorten(s.Detail))
+		}
+		rows = append(rows, line)
+	}
+
+	waiting := "contacting the orchestrator"
+	if n := len(p.stages); n > 0 {
+		waiting = p.stages[n-1].Waiting()
+	}
+	rows = append(rows, m.spinner.View()+" "+lipgloss.NewStyle().Foreground(warn).Render(waiting))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
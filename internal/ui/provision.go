@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/fleet"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Provisioning form fields, in tab order
+const (
+	fieldAlias = iota
+	fieldTier
+	fieldRegion
+	fieldDistro
+	fieldDuration
+	fieldKey
+	fieldPassphrase
+	fieldPayment
+	fieldCount
+)
+
+var fieldLabels = [fieldCount]string{
+	"Alias:     ",
+	"Tier:      ",
+	"Region:    ",
+	"Distro:    ",
+	"Duration:  ",
+	"SSH key:   ",
+	"Key pass:  ",
+	"Payment:   ",
+}
+
+type provisionResultMsg struct {
+	res *fleet.ProvisionResult
+	err error
+}
+
+func newProvisionInputs() []textinput.Model {
+	inputs := make([]textinput.Model, fieldCount)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+	}
+
+	inputs[fieldAlias].Placeholder = "alias (e.g. web-prod)"
+	inputs[fieldAlias].Focus()
+
+	inputs[fieldTier].Placeholder = "tier (eco-small, standard, monster)"
+	inputs[fieldTier].SetValue("eco-small")
+
+	inputs[fieldRegion].Placeholder = "region (nbg1, ash, hil, sin)"
+	inputs[fieldRegion].SetValue("nbg1")
+
+	inputs[fieldDistro].Placeholder = "distro (ubuntu-24.04)"
+	inputs[fieldDistro].SetValue("ubuntu-24.04")
+
+	inputs[fieldDuration].Placeholder = "duration (1h, 24h, 168h)"
+	inputs[fieldDuration].SetValue("1h")
+
+	inputs[fieldKey].Placeholder = "default | per-node | /path/to/key.pub"
+	inputs[fieldKey].SetValue("default")
+
+	inputs[fieldPassphrase].Placeholder = "optional, per-node keys only"
+	inputs[fieldPassphrase].EchoMode = textinput.EchoPassword
+
+	inputs[fieldPayment].Placeholder = "payment (usdc, xmr)"
+	inputs[fieldPayment].SetValue("usdc")
+
+	return inputs
+}
+
+// provisionRequest maps the form onto the request shared with `entropy up`
+func (m Model) provisionRequest() (fleet.ProvisionRequest, error) {
+	v := func(i int) string { return strings.TrimSpace(m.inputs[i].Value()) }
+
+	req := fleet.ProvisionRequest{
+		Alias:    v(fieldAlias),
+		Tier:     v(fieldTier),
+		Region:   v(fieldRegion),
+		Distro:   v(fieldDistro),
+		Duration: v(fieldDuration),
+	}
+
+	if _, err := time.ParseDuration(req.Duration); err != nil {
+		return req, fmt.Errorf("invalid duration %q", req.Duration)
+	}
+
+	switch key := v(fieldKey); key {
+	case "", "default":
+	case "per-node":
+		req.PerNodeKey = true
+		if pass := m.inputs[fieldPassphrase].Value(); pass != "" {
+			req.KeyPassphrase = []byte(pass)
+		}
+	default:
+		req.SSHKeyPath = key
+	}
+	return req, nil
+}
+
+func provisionVM(req fleet.ProvisionRequest, payMethod string, gen int) tea.Cmd {
+	return func() tea.Msg {
+		client, err := newClient(payMethod)
+		if err != nil {
+			return provisionResultMsg{err: err}
+		}
+		reportProgress(client, gen)
+
+		res, err := fleet.Provision(context.Background(), client, req)
+		return provisionResultMsg{res: res, err: err}
+	}
+}
+
+func (m Model) updateProvision(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = stateList
+		return m, nil
+	case "tab", "shift+tab", "up", "down":
+		if msg.String() == "up" || msg.String() == "shift+tab" {
+			m.focusIdx--
+		} else {
+			m.focusIdx++
+		}
+		if m.focusIdx > len(m.inputs)-1 {
+			m.focusIdx = 0
+		} else if m.focusIdx < 0 {
+			m.focusIdx = len(m.inputs) - 1
+		}
+		cmds := make([]tea.Cmd, len(m.inputs))
+		for i := range m.inputs {
+			if i == m.focusIdx {
+				cmds[i] = m.inputs[i].Focus()
+			} else {
+				m.inputs[i].Blur()
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case "enter":
+		// Only one payment at a time; the result would reset this form
+		if m.busy {
+			m.formErr = "another operation is still running"
+			return m, nil
+		}
+		req, err := m.provisionRequest()
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		m.formErr = ""
+		m.state = stateList
+		m.busy = true
+		m.status = "X402_NEGOTIATING_PAYMENT..."
+		gen := m.startProgress("PROVISION " + req.Tier + " @ " + req.Region)
+		return m, tea.Batch(m.spinner.Tick, provisionVM(req, m.inputs[fieldPayment].Value(), gen))
+	case "left", "right":
+		if m.isPicker(m.focusIdx) {
+			delta := 1
+			if msg.String() == "left" {
+				delta = -1
+			}
+			m.cyclePicker(m.focusIdx, delta)
+			return m, nil
+		}
+	}
+
+	if m.isPicker(m.focusIdx) {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focusIdx], cmd = m.inputs[m.focusIdx].Update(msg)
+	return m, cmd
+}
+
+func (m Model) provisionView() string {
+	rows := []string{
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("\n[ PROVISION_NEW_NODE ]"),
+		"",
+	}
+	if note := m.manifestNote(); note != "" {
+		rows = append(rows, note, "")
+	}
+	for i := range m.inputs {
+		if m.isPicker(i) {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, fieldLabels[i], m.pickerView(i)))
+			continue
+		}
+		rows = append(rows, fieldLabels[i]+m.inputs[i].View())
+	}
+	rows = append(rows, "", "Estimate:  "+m.estimateView())
+
+	if m.formErr != "" {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(accent).Render(sym.Fail+" "+m.formErr))
+	}
+
+	rows = append(rows,
+		"",
+		helpStyle.Render("enter: validate & pay • esc: cancel • tab/↑/↓: navigate • ←/→: choose"),
+		lipgloss.NewStyle().Foreground(muted).Italic(true).Render("\nNote: XMR verification may take up to 2 minutes."),
+	)
+	return lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// provisionResultView is shown once after a successful provision. Afterwards
+// the root password is only available through `entropy creds`.
+func (m Model) provisionResultView() string {
+	res := m.lastProvision
+	label := func(s string) string { return lipgloss.NewStyle().Foreground(muted).Render(s) }
+	value := func(s string) string { return lipgloss.NewStyle().Foreground(bright).Render(s) }
+
+	rows := []string{
+		lipgloss.NewStyle().Foreground(good).Bold(true).Render("\n[ PROVISION_SUCCESSFUL ]"),
+		"",
+		label("ID:        ") + value(fmt.Sprintf("%d", res.Response.VM.ProviderID)),
+		label("NAME:      ") + value(res.Response.VM.Name),
+		label("ALIAS:     ") + value(res.VM.Alias),
+		label("IP:        ") + value(res.Response.VM.IP),
+		label("PASSWORD:  ") + lipgloss.NewStyle().Foreground(warn).Bold(true).Render(res.Response.VM.Password),
+		label("EXPIRES:   ") + value(res.Response.VM.ExpiresAt.Format(time.RFC1123)),
+		label("SSH KEY:   ") + value(res.VM.SSHKeyPath),
+	}
+	if res.SaveErr != nil {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(warn).Render(sym.Warn+" VM provisioned but local save failed: "+res.SaveErr.Error()))
+	}
+	note := lipgloss.NewStyle().Foreground(warn).Render("Copy the password now; it is not shown again.")
+	if res.PasswordStored {
+		note = helpStyle.Render("Password saved to the keyring; `entropy creds " + res.VM.Alias + "` shows it again.")
+	}
+	rows = append(rows,
+		"",
+		note,
+		helpStyle.Render("enter/esc: back to fleet"),
+	)
+	return lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
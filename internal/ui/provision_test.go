@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProvisionWaitsForBusyAction(t *testing.T) {
+	m := Model{state: stateList, busy: true, keys: defaultKeyMap(), options: testOptions(), inputs: newProvisionInputs()}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if got := next.(Model); got.state != stateList || cmd != nil {
+		t.Errorf("provision form opened while another action was running")
+	}
+
+	m.state = stateProvisioning
+	m.status = "RENEWING..."
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got := next.(Model)
+	if got.state != stateProvisioning || got.status != "RENEWING..." || got.formErr == "" || cmd != nil {
+		t.Errorf("state %v status %q formErr %q: provision submitted while another action was running", got.state, got.status, got.formErr)
+	}
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/fleet"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type optionsMsg struct {
+	opts *api.Options
+	err  error
+}
+
+type renewResultMsg struct {
+	alias   string
+	expiry  time.Time
+	message string
+	err     error
+}
+
+var payMethods = []string{"usdc", "xmr"}
+
+// renewForm holds the state of the `r` modal
+type renewForm struct {
+	vm       db.LocalVM
+	duration textinput.Model
+	payIdx   int
+}
+
+func newRenewForm(vm db.LocalVM) renewForm {
+	d := textinput.New()
+	d.Placeholder = "duration (1h, 24h, 168h)"
+	d.SetValue("1h")
+	d.Focus()
+	return renewForm{vm: vm, duration: d}
+}
+
+func (f renewForm) payMethod() string {
+	return payMethods[f.payIdx]
+}
+
+func fetchOptions() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	opts, err := api.LoadOptions(ctx)
+	return optionsMsg{opts: opts, err: err}
+}
+
+func renewVM(vm db.LocalVM, duration, payMethod string, gen int) tea.Cmd {
+	return func() tea.Msg {
+		return renewNode(vm, duration, payMethod, gen)
+	}
+}
+
+// renewNode pays for the extension and records the new expiry locally.
+// gen routes x402 stages to the progress panel; bulk renewals pass zero.
+func renewNode(vm db.LocalVM, duration, payMethod string, gen int) renewResultMsg {
+	client, err := newClient(payMethod)
+	if err != nil {
+		return renewResultMsg{alias: vm.Alias, err: err}
+	}
+	reportProgress(client, gen)
+
+	// A renewal paid for but not recorded is still a renewal; the failure is
+	// in the log pane and the next sync corrects the expiry
+	res, err := fleet.Renew(context.Background(), client, vm, duration)
+	if err != nil && !errors.Is(err, fleet.ErrNotRecorded) {
+		return renewResultMsg{alias: vm.Alias, err: err}
+	}
+	expiry, _ := res.ExpiresAt()
+	return renewResultMsg{alias: vm.Alias, expiry: expiry, message: res.Message}
+}
+
+func (m Model) updateRenew(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = stateList
+		return m, nil
+	case "tab", "shift+tab", "left", "right":
+		m.renew.payIdx = (m.renew.payIdx + 1) % len(payMethods)
+		return m, nil
+	case "enter":
+		duration := m.renew.duration.Value()
+		if _, err := time.ParseDuration(duration); err != nil {
+			m.status = "ERROR: INVALID_DURATION " + duration
+			return m, nil
+		}
+		m.state = stateList
+		m.busy = true
+		m.status = fmt.Sprintf("RENEWING_%s_(%s/%s)", m.renew.vm.Alias, duration, m.renew.payMethod())
+		gen := m.startProgress("RENEW " + m.renew.vm.Alias + " +" + duration)
+		return m, tea.Batch(m.spinner.Tick, renewVM(m.renew.vm, duration, m.renew.payMethod(), gen))
+	}
+
+	var cmd tea.Cmd
+	m.renew.duration, cmd = m.renew.duration.Update(msg)
+	return m, cmd
+}
+
+// applyRenewal refreshes the TTL cell of the renewed row without a paid sync
+func (m *Model) applyRenewal(msg renewResultMsg) {
+	if msg.expiry.IsZero() {
+		return
+	}
+	for i, r := range m.fleet {
+		if r.vm.Alias == msg.alias {
+			m.fleet[i].expires = msg.expiry
+			m.fleet[i].vm.ExpiresAt = msg.expiry
+		}
+	}
+	m.refreshTable()
+}
+
+func (m Model) quoteView() string {
+	if m.options == nil {
+		if m.optionsErr != nil {
+			return helpStyle.Render("Quote unavailable: " + m.optionsErr.Error())
+		}
+		return helpStyle.Render("Fetching quote...")
+	}
+	cost, err := m.options.Quote(m.renew.vm.Tier, m.renew.vm.Region, m.renew.duration.Value())
+	if err != nil {
+		return helpStyle.Render("Quote unavailable: " + err.Error())
+	}
+	return lipgloss.NewStyle().Foreground(bright).Render(fmt.Sprintf("~$%.4f USD", cost))
+}
+
+func (m Model) renewView() string {
+	pay := ""
+	for i, p := range payMethods {
+		label := " " + p + " "
+		if i == m.renew.payIdx {
+			pay += selectedStyle.Render(label)
+		} else {
+			pay += helpStyle.Render(label)
+		}
+		pay += " "
+	}
+
+	form := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Foreground(accent).Bold(true).Render("\n[ RENEW_LEASE // "+m.renew.vm.Alias+" ]"),
+		"",
+		helpStyle.Render(fmt.Sprintf("Tier: %s  Region: %s", m.renew.vm.Tier, m.renew.vm.Region)),
+		"",
+		"Duration: ", m.renew.duration.View(),
+		"Payment:  ", pay,
+		"",
+		"Quote:    ", m.quoteView(),
+		"",
+		helpStyle.Render("enter: pay & renew • tab/←/→: payment • esc: cancel"),
+		lipgloss.NewStyle().Foreground(muted).Italic(true).Render("\nNote: XMR verification may take up to 2 minutes."),
+	)
+	return lipgloss.NewStyle().Padding(1, 4).Render(form)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	idleBackoffAfter = 5 * time.Minute
+	idlePauseAfter   = 30 * time.Minute
+	backoffFactor    = 4
+)
+
+type settlementMsg api.Settlement
+
+// settlements carries payments from clients running inside tea.Cmds back to the model
+var settlements = make(chan api.Settlement, 64)
+
+// newClient is api.NewClient with settlement reporting wired into the dashboard
+func newClient(payMethod string) (*api.Client, error) {
+	client, err := api.NewClient(payMethod)
+	if err != nil {
+		return nil, err
+	}
+	client.OnSettlement = func(s api.Settlement) {
+		select {
+		case settlements <- s:
+		default:
+		}
+	}
+	return client, nil
+}
+
+func waitForSettlement() tea.Msg {
+	return settlementMsg(<-settlements)
+}
+
+// effectiveSyncInterval applies idle and focus backoff. Zero means no auto-sync.
+func (m Model) effectiveSyncInterval() time.Duration {
+	if !m.autoSync || m.syncEvery == 0 {
+		return 0
+	}
+	idle := time.Since(m.lastInput)
+	if idle > idlePauseAfter {
+		return 0
+	}
+	if !m.focused || idle > idleBackoffAfter {
+		return m.syncEvery * backoffFactor
+	}
+	return m.syncEvery
+}
+
+func (m Model) syncStatusView() string {
+	every := m.effectiveSyncInterval()
+	switch {
+	case !m.autoSync || m.syncEvery == 0:
+		return " [i] MANUAL SYNC • " + m.keys.Sync.Help().Key + " refreshes (each /list is a paid request)"
+	case every == 0:
+		return " [i] AUTO-SYNC PAUSED (idle) • press any key to resume"
+	case every != m.syncEvery:
+		return fmt.Sprintf(" [!] AUTO-SYNC BACKED OFF: every %s (idle/unfocused)", every)
+	}
+	return fmt.Sprintf(" [!] AUTO-SYNC ACTIVE: every %s (each /list is a paid request)", every)
+}
+
+func (m Model) spendView() string {
+	if len(m.spend) == 0 {
+		return "SESSION_SPEND: 0"
+	}
+	units := make([]string, 0, len(m.spend))
+	for u := range m.spend {
+		units = append(units, u)
+	}
+	sort.Strings(units)
+
+	parts := make([]string, 0, len(units))
+	for _, u := range units {
+		parts = append(parts, fmt.Sprintf("%.6f %s", m.spend[u], u))
+	}
+	return fmt.Sprintf("SESSION_SPEND: %s (%d payments)", strings.Join(parts, " + "), m.payments)
+}
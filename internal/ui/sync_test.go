@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/x402-Systems/entropy/internal/db"
+)
+
+func TestSyncErrorLeavesProvisionAlone(t *testing.T) {
+	m := Model{state: stateProvisioning, busy: true, status: "PROVISIONING..."}
+	next, _ := m.Update(syncErrMsg{err: errors.New("no identity linked")})
+	got := next.(Model)
+	if !got.busy || got.state != stateProvisioning || got.status != "PROVISIONING..." {
+		t.Fatalf("busy=%v state=%v status=%q after a sync error during provisioning", got.busy, got.state, got.status)
+	}
+
+	m = Model{state: stateList}
+	next, _ = m.Update(syncErrMsg{err: errors.New("no identity linked")})
+	if got := next.(Model).status; got != "SYNC_ERROR: no identity linked" {
+		t.Fatalf("status = %q", got)
+	}
+}
+
+func TestSyncErrorKeepsRows(t *testing.T) {
+	rows := []fleetRow{{vm: db.LocalVM{Alias: "web-1"}, status: "ALIVE"}}
+	m := Model{state: stateList, fleet: rows}
+	next, _ := m.Update(syncErrMsg{err: errors.New("server error (502): bad gateway")})
+	got := next.(Model)
+	if len(got.fleet) != 1 || got.fleet[0].status != "ALIVE" {
+		t.Fatalf("rows after a failed sync: %+v", got.fleet)
+	}
+}
+
+func TestSyncReportsCostError(t *testing.T) {
+	m := Model{state: stateList}
+	next, _ := m.Update(syncMsg{costErr: errors.New("no such table: payments")})
+	if got := next.(Model).status; got != "FLEET_SYNCED, COST_ERROR: no such table: payments" {
+		t.Errorf("status = %q", got)
+	}
+}
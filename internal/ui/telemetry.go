@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/sshmgr"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	telemetryEvery   = 5 * time.Second
+	telemetryHistory = 24
+)
+
+type telemetryMsg struct {
+	gen    int
+	sample sshmgr.Sample
+	err    error
+}
+
+// telemetryEvents carries readings from the collector goroutine to the model
+var telemetryEvents = make(chan telemetryMsg, 8)
+
+func waitForTelemetry() tea.Msg {
+	return <-telemetryEvents
+}
+
+// telemetry tracks the collector for the selected node. gen increases on every
+// restart so readings from a collector that was just stopped are ignored.
+type telemetry struct {
+	enabled bool
+	alias   string
+	gen     int
+	cancel  context.CancelFunc
+
+	last            *sshmgr.Sample
+	load, mem, disk []float64
+	err             string
+}
+
+// collect holds one SSH connection open and polls it until ctx is cancelled
+func collect(ctx context.Context, gen int, vm db.LocalVM) {
+	send := func(msg telemetryMsg) bool {
+		select {
+		case telemetryEvents <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ticker := time.NewTicker(telemetryEvery)
+	defer ticker.Stop()
+
+	// One connection is kept for the whole selection and only re-dialled
+	// after it breaks. A nil prompt means encrypted keys need ssh-agent.
+	var client *ssh.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	for {
+		var msg telemetryMsg
+		if client == nil {
+			client, msg.err = sshmgr.Connect(vm.IP, vm.SSHKeyPath, nil)
+		}
+		if client != nil {
+			msg.sample, msg.err = sshmgr.Collect(client)
+			if msg.err != nil {
+				client.Close()
+				client = nil
+			}
+		}
+		msg.gen = gen
+		if !send(msg) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncCollector starts, moves or stops the collector to follow the selected
+// row. Only ALIVE nodes are polled.
+func (m *Model) syncCollector() {
+	target := ""
+	if curr := m.table.SelectedRow(); m.telemetry.enabled && len(curr) > 0 && curr[colStatus] == "ALIVE" {
+		target = curr[colAlias]
+	}
+	if target == m.telemetry.alias {
+		return
+	}
+
+	if m.telemetry.cancel != nil {
+		m.telemetry.cancel()
+	}
+	m.telemetry = telemetry{enabled: m.telemetry.enabled, gen: m.telemetry.gen + 1, alias: target}
+	if target == "" {
+		return
+	}
+
+	var vm db.LocalVM
+	if err := db.DB.Where("alias = ?", target).First(&vm).Error; err != nil {
+		m.telemetry.err = err.Error()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.telemetry.cancel = cancel
+	go collect(ctx, m.telemetry.gen, vm)
+}
+
+func (m *Model) applyTelemetry(msg telemetryMsg) {
+	if msg.gen != m.telemetry.gen {
+		return
+	}
+	if msg.err != nil {
+		m.telemetry.err = msg.err.Error()
+		return
+	}
+	s := msg.sample
+	m.telemetry.err = ""
+	m.telemetry.last = &s
+	m.telemetry.load = pushSample(m.telemetry.load, s.Load1)
+	m.telemetry.mem = pushSample(m.telemetry.mem, ratio(s.MemUsed, s.MemTotal))
+	m.telemetry.disk = pushSample(m.telemetry.disk, ratio(s.DiskUsed, s.DiskTotal))
+}
+
+func pushSample(h []float64, v float64) []float64 {
+	h = append(h, v)
+	if len(h) > telemetryHistory {
+		h = h[len(h)-telemetryHistory:]
+	}
+	return h
+}
+
+func ratio(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline scales values against ceil; a zero ceil scales to the series max
+func sparkline(values []float64, ceil float64) string {
+	if ceil == 0 {
+		for _, v := range values {
+			ceil = max(ceil, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if ceil > 0 {
+			i = int(v / ceil * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[min(max(i, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
+func humanBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func (m Model) telemetryView() string {
+	label := func(s string) string { return lipgloss.NewStyle().Foreground(muted).Render(s) }
+	value := func(s string) string { return lipgloss.NewStyle().Foreground(bright).Render(s) }
+	spark := func(s string) string { return lipgloss.NewStyle().Foreground(accent).Render(s) }
+
+	t := m.telemetry
+	switch {
+	case t.alias == "":
+		return helpStyle.Render("Telemetry needs an ALIVE node.")
+	case t.err != "":
+		return lipgloss.NewStyle().Foreground(warn).Render(sym.Warn + " telemetry: " + t.err)
+	case t.last == nil:
+		return helpStyle.Render("Connecting to " + t.alias + "...")
+	}
+
+	s := t.last
+	ports := make([]string, len(s.Ports))
+	for i, p := range s.Ports {
+		ports[i] = fmt.Sprint(p)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		label("LOAD_1M:       ")+value(fmt.Sprintf("%-6.2f ", s.Load1))+spark(sparkline(t.load, 0)),
+		label("MEMORY:        ")+value(fmt.Sprintf("%-6s ", humanBytes(s.MemUsed)))+spark(sparkline(t.mem, 1)),
+		label("DISK:          ")+value(fmt.Sprintf("%-6s ", fmt.Sprintf("%.0f%%", ratio(s.DiskUsed, s.DiskTotal)*100)))+spark(sparkline(t.disk, 1)),
+		label("UPTIME:        ")+value(s.Uptime.Round(time.Minute).String()),
+		label("LISTENING:     ")+value(strings.Join(ports, ", ")),
+	)
+}
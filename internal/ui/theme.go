@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"github.com/x402-Systems/entropy/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette and shared styles, set once from the configured theme by InitialModel
+var (
+	accent   lipgloss.TerminalColor
+	onAccent lipgloss.TerminalColor
+	good     lipgloss.TerminalColor
+	warn     lipgloss.TerminalColor
+	muted    lipgloss.TerminalColor
+	bright   lipgloss.TerminalColor
+
+	headerStyle   lipgloss.Style
+	borderStyle   lipgloss.Style
+	helpStyle     lipgloss.Style
+	selectedStyle lipgloss.Style
+
+	sym theme.Symbols
+)
+
+func init() {
+	applyTheme(theme.Load(""))
+}
+
+func applyTheme(t theme.Theme) {
+	accent, onAccent, good, warn, muted, bright = t.Accent, t.OnAccent, t.Good, t.Warn, t.Muted, t.Text
+	sym = t.Symbols
+
+	headerStyle = lipgloss.NewStyle().Foreground(onAccent).Background(accent).Padding(0, 1).Bold(true).Italic(true)
+	borderStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderLeftForeground(accent).PaddingLeft(2)
+	helpStyle = lipgloss.NewStyle().Foreground(muted)
+	selectedStyle = t.Selected()
+	if t.Mono() {
+		headerStyle = headerStyle.Reverse(true)
+	}
+}
+
+func helpStyles() help.Styles {
+	s := help.Styles{
+		ShortKey:       lipgloss.NewStyle().Foreground(bright).Bold(true),
+		ShortDesc:      helpStyle,
+		ShortSeparator: helpStyle,
+		Ellipsis:       helpStyle,
+		FullKey:        lipgloss.NewStyle().Foreground(accent).Bold(true),
+		FullDesc:       lipgloss.NewStyle().Foreground(bright),
+		FullSeparator:  helpStyle,
+	}
+	return s
+}
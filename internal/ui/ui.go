@@ -5,16 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/url"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/x402-Systems/entropy/internal/api"
 	"github.com/x402-Systems/entropy/internal/config"
 	"github.com/x402-Systems/entropy/internal/db"
+	"github.com/x402-Systems/entropy/internal/eventlog"
+	"github.com/x402-Systems/entropy/internal/fleet"
 	"github.com/x402-Systems/entropy/internal/sshmgr"
+	"github.com/x402-Systems/entropy/internal/theme"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,29 +30,37 @@ type sessionState int
 const (
 	stateList sessionState = iota
 	stateProvisioning
+	stateRenewing
+	stateProvisionResult
+	stateCommand
+	stateDestroying
+	stateBulk
+)
+
+// Fleet table columns
+const (
+	colMark = iota
+	colAlias
+	colStatus
+	colIP
+	colTTL
+	colRegion
+	colCost
+	colRenewals
+	colAge
 )
 
 type syncMsg struct {
-	rows    []table.Row
+	rows    []fleetRow
 	remotes map[int64]api.RemoteVM
-}
-type tickMsg time.Time
-type statusMsg string
-type provisionResultMsg struct {
-	err error
+	// costErr leaves the COST column empty but the rows are current
+	costErr error
 }
 
-var (
-	red    = lipgloss.Color("#FF0000")
-	green  = lipgloss.Color("#00FF00")
-	yellow = lipgloss.Color("#F1C40F")
-	grey   = lipgloss.Color("#444444")
-	white  = lipgloss.Color("#FFFFFF")
-
-	headerStyle = lipgloss.NewStyle().Foreground(white).Background(red).Padding(0, 1).Bold(true).Italic(true)
-	borderStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderLeftForeground(red).PaddingLeft(2)
-	helpStyle   = lipgloss.NewStyle().Foreground(grey)
-)
+// syncErrMsg is a background sync that failed; the last rows stay and any
+// in-flight provision or renewal is left alone
+type syncErrMsg struct{ err error }
+type tickMsg time.Time
 
 type Model struct {
 	state    sessionState
@@ -57,120 +69,119 @@ type Model struct {
 	focusIdx int
 	wallet   string
 	status   string
-	SSHToRun string
 	remotes  map[int64]api.RemoteVM
+	fleet    []fleetRow
+	list     listView
+	marked   map[string]bool
 	width    int
 	height   int
 	lastSync time.Time
+
+	formErr       string
+	lastProvision *fleet.ProvisionResult
+
+	renew      renewForm
+	command    commandForm
+	logs       logPane
+	telemetry  telemetry
+	keys       keyMap
+	help       help.Model
+	showHelp   bool
+	destroy    destroyForm
+	bulk       bulkForm
+	sshQueue   []db.LocalVM
+	options    *api.Options
+	optionsErr error
+	spinner    spinner.Model
+	busy       bool
+
+	syncEvery time.Duration
+	autoSync  bool
+	focused   bool
+	lastInput time.Time
+	spend     map[string]float64
+	payments  int
+	progress  paymentProgress
+
+	widgets      widgetsMsg
+	evmRPC       string
+	usdcContract string
 }
 
-func InitialModel(walletAddr string) Model {
+// Config carries the user's settings into the dashboard
+type Config struct {
+	// SyncEvery is the paid auto-sync interval; zero starts in manual mode
+	SyncEvery time.Duration
+	Theme     theme.Theme
+	// Keys overrides bindings by action name, as read from keys.json
+	Keys map[string][]string
+	// EVMRPC and USDCContract locate the USDC balance shown in the header
+	EVMRPC       string
+	USDCContract string
+}
+
+// InitialModel builds the dashboard
+func InitialModel(walletAddr string, cfg Config) Model {
+	applyTheme(cfg.Theme)
+	syncEvery := cfg.SyncEvery
+
 	columns := []table.Column{
-		{Title: "ALIAS", Width: 25},
+		{Title: "", Width: 1},
+		{Title: "ALIAS", Width: 18},
 		{Title: "STATUS", Width: 10},
 		{Title: "IP_ADDR", Width: 16},
-		{Title: "TTL", Width: 15}, // Widened slightly
+		{Title: "TTL", Width: 12},
 		{Title: "REGION", Width: 8},
+		{Title: "COST", Width: 12},
+		{Title: "RENEWALS", Width: 8},
+		{Title: "AGE", Width: 6},
 	}
 	t := table.New(table.WithColumns(columns), table.WithFocused(true))
 	s := table.DefaultStyles()
-	s.Header = s.Header.BorderStyle(lipgloss.NormalBorder()).BorderForeground(grey).BorderBottom(true).Bold(false)
-	s.Selected = s.Selected.Foreground(white).Background(red).Bold(true)
+	s.Header = s.Header.BorderStyle(lipgloss.NormalBorder()).BorderForeground(muted).BorderBottom(true).Bold(false)
+	s.Selected = selectedStyle
 	t.SetStyles(s)
 
-	inputs := make([]textinput.Model, 5)
-	inputs[0] = textinput.New()
-	inputs[0].Placeholder = "alias (e.g. web-prod)"
-	inputs[0].Focus()
-
-	inputs[1] = textinput.New()
-	inputs[1].Placeholder = "tier (eco-small, pro)"
-	inputs[1].SetValue("eco-small")
-
-	inputs[2] = textinput.New()
-	inputs[2].Placeholder = "region (nbg1, ash)"
-	inputs[2].SetValue("nbg1")
+	keys, unknown := defaultKeyMap().withOverrides(cfg.Keys)
+	status := "IDLE"
+	if len(unknown) > 0 {
+		status = "UNKNOWN_KEY_ACTIONS: " + strings.Join(unknown, ",")
+	}
+	h := help.New()
+	h.Styles = helpStyles()
 
-	inputs[3] = textinput.New()
-	inputs[3].Placeholder = "duration (1h, 24h)"
-	inputs[3].SetValue("1h")
+	inputs := newProvisionInputs()
 
-	inputs[4] = textinput.New()
-	inputs[4].Placeholder = "payment (usdc, xmr)"
-	inputs[4].SetValue("usdc")
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+	if cfg.Theme.Mono() {
+		sp.Spinner = spinner.Line
+	}
+	sp.Style = lipgloss.NewStyle().Foreground(accent)
 
 	return Model{
+		spinner:  sp,
 		state:    stateList,
 		table:    t,
+		list:     newListView(),
+		marked:   make(map[string]bool),
+		logs:     newLogPane(),
 		inputs:   inputs,
 		wallet:   walletAddr,
-		status:   "IDLE",
+		status:   status,
+		keys:     keys,
+		help:     h,
 		remotes:  make(map[int64]api.RemoteVM),
 		lastSync: time.Now(),
-	}
-}
 
-func provisionVM(alias, tier, region, duration, payMethod string) tea.Cmd {
-	return func() tea.Msg {
-		client, err := api.NewClient(payMethod)
-		if err != nil {
-			return provisionResultMsg{err: err}
-		}
+		syncEvery: syncEvery,
+		autoSync:  syncEvery > 0,
+		focused:   true,
+		lastInput: time.Now(),
+		spend:     make(map[string]float64),
 
-		sshPath, err := sshmgr.GetDefaultKey()
-		if err != nil {
-			return provisionResultMsg{err: err}
-		}
-		keyContent, _ := os.ReadFile(sshPath)
-
-		params := url.Values{}
-		params.Add("tier", tier)
-		params.Add("distro", "ubuntu-24.04")
-		params.Add("duration", duration)
-		params.Add("ssh_key", strings.TrimSpace(string(keyContent)))
-
-		headers := map[string]string{
-			"X-VM-TIER":     tier,
-			"X-VM-DURATION": duration,
-			"X-VM-REGION":   region,
-		}
-
-		resp, err := client.DoRequest(context.Background(), "POST", "/provision?"+params.Encode(), nil, headers)
-		if err != nil {
-			return provisionResultMsg{err: err}
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			body, _ := io.ReadAll(resp.Body)
-			return provisionResultMsg{err: fmt.Errorf("server: %s", string(body))}
-		}
-
-		var res struct {
-			VM struct {
-				ProviderID int64     `json:"ProviderID"`
-				Name       string    `json:"Name"`
-				IP         string    `json:"IP"`
-				Tier       string    `json:"Tier"`
-				Region     string    `json:"Region"`
-				ExpiresAt  time.Time `json:"ExpiresAt"`
-			} `json:"vm"`
-		}
-		json.NewDecoder(resp.Body).Decode(&res)
-
-		db.DB.Create(&db.LocalVM{
-			ProviderID:  res.VM.ProviderID,
-			ServerName:  res.VM.Name,
-			Alias:       alias,
-			IP:          res.VM.IP,
-			Tier:        res.VM.Tier,
-			Region:      res.VM.Region,
-			ExpiresAt:   res.VM.ExpiresAt,
-			SSHKeyPath:  sshPath,
-			OwnerWallet: client.PayerID,
-		})
-
-		return provisionResultMsg{err: nil}
+		evmRPC:       cfg.EVMRPC,
+		usdcContract: cfg.USDCContract,
 	}
 }
 
@@ -178,44 +189,48 @@ func syncData() tea.Msg {
 	var locals []db.LocalVM
 	db.DB.Order("expires_at desc").Find(&locals)
 
-	client, err := api.NewClient("usdc")
+	client, err := newClient("usdc")
 	if err != nil {
-		return provisionResultMsg{err: err}
+		return syncErrMsg{err: err}
 	}
 
+	// Without a full /list every node would look DEAD; keep the last rows
 	resp, err := client.DoRequest(context.Background(), "GET", "/list", nil, nil)
+	if err != nil {
+		return syncErrMsg{err: err}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return syncErrMsg{err: fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))}
+	}
+	var listResp api.ListResponse
+	if err := json.Unmarshal(body, &listResp); err != nil {
+		return syncErrMsg{err: fmt.Errorf("bad /list response: %w", err)}
+	}
 	remotes := make(map[int64]api.RemoteVM)
-	if err == nil {
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		var listResp api.ListResponse
-		json.Unmarshal(body, &listResp)
-		for _, r := range listResp.VMs {
-			remotes[r.ProviderID] = r
-		}
+	for _, r := range listResp.VMs {
+		remotes[r.ProviderID] = r
 	}
+	fleet.PruneCredentials(client.PayerID, remotes)
 
-	rows := []table.Row{}
+	costs, costErr := fleet.Costs()
+	rows := make([]fleetRow, 0, len(locals))
 	for _, l := range locals {
-		statusText := "DEAD"
-		ttl := "0s"
-
+		row := fleetRow{vm: l, status: "DEAD", expires: l.ExpiresAt, cost: costs[l.ProviderID]}
 		if r, ok := remotes[l.ProviderID]; ok {
+			row.expires = r.ExpiresAt
 			// Check Status from server
 			if r.Status == "suspended" {
-				statusText = "PAUSED"
-				ttl = "GRACE PERIOD"
+				row.status = "PAUSED"
 			} else {
-				statusText = "ALIVE"
-				remaining := time.Until(r.ExpiresAt).Round(time.Second)
-				if remaining > 0 {
-					ttl = remaining.String()
-				}
+				row.status = "ALIVE"
 			}
 		}
-		rows = append(rows, table.Row{l.Alias, statusText, l.IP, ttl, l.Region})
+		rows = append(rows, row)
 	}
-	return syncMsg{rows: rows, remotes: remotes}
+	sshmgr.SyncConfig()
+	return syncMsg{rows: rows, remotes: remotes, costErr: costErr}
 }
 
 func doTick() tea.Cmd {
@@ -223,118 +238,348 @@ func doTick() tea.Cmd {
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(syncData, doTick())
+	return tea.Batch(syncData, doTick(), waitForSettlement, waitForLog, waitForTelemetry, waitForProgress, fetchWidgets(m.evmRPC, m.usdcContract))
 }
 
+// Update lets the telemetry collector follow whatever row ends up selected
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		nm.syncCollector()
+		return nm, cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.table.SetHeight(m.height - 14)
+		m.layout()
+		m.command.output.Width = max(m.width-8, 20)
+		m.command.output.Height = max(m.height-16, 5)
 
 	case syncMsg:
 		m.remotes = msg.remotes
-		m.table.SetRows(msg.rows)
+		m.fleet = msg.rows
+		m.refreshTable()
 		m.status = "FLEET_SYNCED"
+		if msg.costErr != nil {
+			m.status = "FLEET_SYNCED, COST_ERROR: " + msg.costErr.Error()
+		}
 		m.lastSync = time.Now()
 
+	case syncErrMsg:
+		if !m.busy {
+			m.status = "SYNC_ERROR: " + msg.err.Error()
+		}
+
 	case tickMsg:
 		if m.state == stateList {
-			if time.Since(m.lastSync) > 30*time.Second {
+			m.refreshTable()
+			if every := m.effectiveSyncInterval(); every > 0 && time.Since(m.lastSync) > every {
+				m.lastSync = time.Now()
 				return m, tea.Batch(doTick(), syncData)
 			}
 		}
 		return m, doTick()
 
-	case provisionResultMsg:
-		m.state = stateList
+	case tea.FocusMsg:
+		m.focused = true
+		return m, nil
+
+	case tea.BlurMsg:
+		m.focused = false
+		return m, nil
+
+	case settlementMsg:
+		amount, unit := api.Settlement(msg).Value()
+		m.spend[unit] += amount
+		m.payments++
+		return m, waitForSettlement
+
+	case spinner.TickMsg:
+		if !m.busy {
+			return m, nil
+		}
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case optionsMsg:
+		// A failed refresh keeps the cached manifest already on screen
+		if msg.opts != nil || m.options == nil {
+			m.options = msg.opts
+			m.optionsErr = msg.err
+		}
+		m.normalizePickers()
+		return m, nil
+
+	case renewResultMsg:
+		m.busy = false
+		m.finishProgress()
 		if msg.err != nil {
 			m.status = "ERROR: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = "RENEWED_" + msg.alias
+		m.applyRenewal(msg)
+		return m, nil
+
+	case sshDoneMsg:
+		if msg.err != nil {
+			m.status = "SSH_ERROR: " + msg.err.Error()
 		} else {
-			m.status = "PROVISION_SUCCESS"
+			m.status = "SSH_CLOSED_" + msg.alias
 		}
+		if len(m.sshQueue) > 0 {
+			next := m.sshQueue[0]
+			m.sshQueue = m.sshQueue[1:]
+			return m, sshSession(next)
+		}
+		return m, nil
+
+	case progressMsg:
+		m.applyProgress(msg)
+		return m, waitForProgress
+
+	case widgetsMsg:
+		m.widgets = msg
+		return m, m.scheduleWidgets()
+
+	case telemetryMsg:
+		m.applyTelemetry(msg)
+		return m, waitForTelemetry
+
+	case logMsg:
+		m.logs.append(eventlog.Entry(msg))
+		return m, waitForLog
+
+	case bulkEventMsg:
+		return m, m.applyBulkEvent(msg)
+
+	case destroyResultMsg:
+		m.applyDestroy(msg)
+		return m, nil
+
+	case commandResultMsg:
+		m.applyCommandResult(msg)
+		return m, nil
+
+	case provisionResultMsg:
+		m.busy = false
+		m.finishProgress()
+		if msg.err != nil {
+			m.status = "ERROR: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = "PROVISION_SUCCESS"
+		m.lastProvision = msg.res
+		m.state = stateProvisionResult
+		m.inputs = newProvisionInputs()
+		m.normalizePickers()
+		m.focusIdx = 0
 		return m, syncData
 
 	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		if m.state == stateRenewing {
+			return m.updateRenew(msg)
+		}
 		if m.state == stateProvisioning {
+			return m.updateProvision(msg)
+		}
+		if m.state == stateCommand {
+			return m.updateCommand(msg)
+		}
+		if m.state == stateDestroying {
+			return m.updateDestroy(msg)
+		}
+		if m.state == stateBulk {
+			return m.updateBulk(msg)
+		}
+		if m.list.filtering {
+			return m.updateFilter(msg)
+		}
+		if m.state == stateProvisionResult {
 			switch msg.String() {
-			case "esc":
+			case "enter", "esc", "q":
 				m.state = stateList
-				return m, nil
-			case "tab", "shift+tab", "up", "down":
-				if msg.String() == "up" || msg.String() == "shift+tab" {
-					m.focusIdx--
-				} else {
-					m.focusIdx++
-				}
-				if m.focusIdx > len(m.inputs)-1 {
-					m.focusIdx = 0
-				} else if m.focusIdx < 0 {
-					m.focusIdx = len(m.inputs) - 1
-				}
-				cmds := make([]tea.Cmd, len(m.inputs))
-				for i := range m.inputs {
-					if i == m.focusIdx {
-						cmds[i] = m.inputs[i].Focus()
-					} else {
-						m.inputs[i].Blur()
-					}
-				}
-				return m, tea.Batch(cmds...)
-			case "enter":
-				m.status = "X402_NEGOTIATING_PAYMENT..."
-				return m, provisionVM(
-					m.inputs[0].Value(),
-					m.inputs[1].Value(),
-					m.inputs[2].Value(),
-					m.inputs[3].Value(),
-					m.inputs[4].Value(),
-				)
-			}
-			for i := range m.inputs {
-				m.inputs[i], cmd = m.inputs[i].Update(msg)
+				m.lastProvision = nil
 			}
-			return m, cmd
+			return m, nil
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
-		case "n":
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.New):
+			if m.busy {
+				return m, nil
+			}
 			m.state = stateProvisioning
+			if m.needOptions() {
+				return m, fetchOptions
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Mark):
+			if curr := m.table.SelectedRow(); len(curr) > 0 {
+				if m.marked[curr[colAlias]] {
+					delete(m.marked, curr[colAlias])
+				} else {
+					m.marked[curr[colAlias]] = true
+				}
+				m.table.MoveDown(1)
+				m.refreshTable()
+			}
 			return m, nil
-		case "ctrl+r":
+		case key.Matches(msg, m.keys.MarkAll):
+			all := true
+			for _, r := range m.table.Rows() {
+				all = all && m.marked[r[colAlias]]
+			}
+			for _, r := range m.table.Rows() {
+				if all {
+					delete(m.marked, r[colAlias])
+				} else {
+					m.marked[r[colAlias]] = true
+				}
+			}
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.ClearMarks):
+			m.marked = make(map[string]bool)
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Telemetry):
+			m.telemetry.enabled = !m.telemetry.enabled
+			return m, nil
+		case key.Matches(msg, m.keys.Log):
+			m.logs.visible = !m.logs.visible
+			m.layout()
+			return m, nil
+		case m.logs.visible && key.Matches(msg, m.keys.LogUp):
+			m.logs.view.HalfPageUp()
+			return m, nil
+		case m.logs.visible && key.Matches(msg, m.keys.LogDown):
+			m.logs.view.HalfPageDown()
+			return m, nil
+		case key.Matches(msg, m.keys.Filter):
+			m.list.filtering = true
+			return m, m.list.filter.Focus()
+		case key.Matches(msg, m.keys.HideDead):
+			m.list.hideDead = !m.list.hideDead
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.HideExpired):
+			m.list.hideExpired = !m.list.hideExpired
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Sort):
+			m.list.sortBy = (m.list.sortBy + 1) % sortKeyCount
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Reverse):
+			m.list.reverse = !m.list.reverse
+			m.refreshTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Sync):
 			m.status = "FORCING_SYNC..."
 			return m, syncData
-		case "s":
-			curr := m.table.SelectedRow()
-			if len(curr) > 0 && curr[1] == "ALIVE" {
-				m.SSHToRun = curr[0]
-				return m, tea.Quit
-			} else if len(curr) > 0 && curr[1] == "PAUSED" {
-				m.status = "VM_IS_PAUSED_RENEW_TO_ACCESS"
+		case key.Matches(msg, m.keys.AutoSync):
+			m.autoSync = !m.autoSync
+			if m.autoSync && m.syncEvery == 0 {
+				m.syncEvery = config.DefaultSyncInterval
+			}
+			if m.autoSync {
+				m.status = "AUTO_SYNC_ON"
+			} else {
+				m.status = "AUTO_SYNC_OFF"
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Renew):
+			if len(m.marked) > 0 {
+				return m.startBulk(bulkRenew)
 			}
-		case "d":
 			curr := m.table.SelectedRow()
-			if len(curr) > 0 {
-				alias := curr[0]
-				m.status = "DESTROYING_" + alias
-				return m, func() tea.Msg {
-					var vm db.LocalVM
-					if err := db.DB.Where("alias = ?", alias).First(&vm).Error; err == nil {
-						client, _ := api.NewClient("usdc")
-						params := url.Values{}
-						params.Add("vm_name", vm.ServerName)
-						client.DoRequest(context.Background(), "DELETE", "/provision?"+params.Encode(), nil, nil)
-						db.DB.Delete(&vm)
-						return statusMsg("DESTROYED_" + alias)
+			if len(curr) == 0 || m.busy {
+				return m, nil
+			}
+			var vm db.LocalVM
+			if err := db.DB.Where("alias = ?", curr[colAlias]).First(&vm).Error; err != nil {
+				m.status = "NOT_FOUND"
+				return m, nil
+			}
+			m.renew = newRenewForm(vm)
+			m.state = stateRenewing
+			if m.needOptions() {
+				return m, fetchOptions
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.SSH, m.keys.Exec):
+			isSSH := key.Matches(msg, m.keys.SSH)
+			if isSSH && len(m.marked) > 0 {
+				var live []db.LocalVM
+				for _, vm := range m.markedVMs() {
+					if m.rowStatus(vm.Alias) == "ALIVE" {
+						live = append(live, vm)
 					}
-					return statusMsg("NOT_FOUND")
 				}
+				if len(live) == 0 {
+					m.status = "NO_ALIVE_NODES_MARKED"
+					return m, nil
+				}
+				return m.openSSHTabs(live)
 			}
+			curr := m.table.SelectedRow()
+			if len(curr) > 0 && curr[colStatus] == "PAUSED" {
+				m.status = "VM_IS_PAUSED_RENEW_TO_ACCESS"
+				return m, nil
+			}
+			if len(curr) == 0 || curr[colStatus] != "ALIVE" {
+				return m, nil
+			}
+			var vm db.LocalVM
+			if err := db.DB.Where("alias = ?", curr[colAlias]).First(&vm).Error; err != nil {
+				m.status = "NOT_FOUND"
+				return m, nil
+			}
+			if isSSH {
+				m.status = "SSH_" + vm.Alias
+				return m, sshSession(vm)
+			}
+			m.command = newCommandForm(vm, m.width, m.height)
+			m.state = stateCommand
+			return m, nil
+		case key.Matches(msg, m.keys.Destroy):
+			if len(m.marked) > 0 {
+				return m.startBulk(bulkDestroy)
+			}
+			curr := m.table.SelectedRow()
+			if len(curr) == 0 || m.busy {
+				return m, nil
+			}
+			var vm db.LocalVM
+			if err := db.DB.Where("alias = ?", curr[colAlias]).First(&vm).Error; err != nil {
+				m.status = "NOT_FOUND"
+				return m, nil
+			}
+			m.destroy = newDestroyForm(vm)
+			m.state = stateDestroying
+			return m, nil
 		}
 	}
 
@@ -348,49 +593,61 @@ func (m Model) View() string {
 	}
 
 	header := headerStyle.Render(fmt.Sprintf("X402_SYSTEMS // AGENT_TERMINAL_%s", config.Version))
-	wallet := lipgloss.NewStyle().Foreground(grey).Render(" AUTH_ID: " + m.wallet)
+	wallet := lipgloss.NewStyle().Foreground(muted).Render(" AUTH_ID: "+m.wallet) +
+		lipgloss.NewStyle().Foreground(warn).Render("  "+m.spendView())
 
 	var mainContent string
-	if m.state == stateProvisioning {
-		form := lipgloss.JoinVertical(lipgloss.Left,
-			lipgloss.NewStyle().Foreground(red).Bold(true).Render("\n[ PROVISION_NEW_NODE ]"),
-			"",
-			"Alias:    ", m.inputs[0].View(),
-			"Tier:     ", m.inputs[1].View(),
-			"Region:   ", m.inputs[2].View(),
-			"Duration: ", m.inputs[3].View(),
-			"Payment:  ", m.inputs[4].View(),
-			"",
-			helpStyle.Render("enter: confirm • esc: cancel • tab: navigate"),
-			lipgloss.NewStyle().Foreground(grey).Italic(true).Render("\nNote: XMR verification may take up to 2 minutes."),
-		)
-		mainContent = lipgloss.NewStyle().Padding(1, 4).Render(form)
+	if m.state == stateRenewing {
+		mainContent = m.renewView()
+	} else if m.state == stateProvisionResult {
+		mainContent = m.provisionResultView()
+	} else if m.state == stateProvisioning {
+		mainContent = m.provisionView()
+	} else if m.state == stateBulk {
+		mainContent = m.bulkView()
+	} else if m.state == stateDestroying {
+		mainContent = m.destroyView()
+	} else if m.state == stateCommand {
+		mainContent = m.commandView()
 	} else {
-		tableBox := m.table.View()
+		tableBox := lipgloss.JoinVertical(lipgloss.Left, m.listStatusView(), m.table.View())
 		currRow := m.table.SelectedRow()
 		var details string
 		if len(currRow) > 0 {
 			// Color Logic for Status
-			stColor := grey
+			stColor := muted
 			hintText := ""
 
-			if currRow[1] == "ALIVE" {
-				stColor = green
-			} else if currRow[1] == "PAUSED" {
-				stColor = yellow
-				hintText = lipgloss.NewStyle().Foreground(yellow).Render("\n⚠ VM IS SUSPENDED\nRun 'entropy renew " + currRow[0] + "' to restore.")
+			if currRow[colStatus] == "ALIVE" {
+				stColor = good
+			} else if currRow[colStatus] == "PAUSED" {
+				stColor = warn
+				hintText = lipgloss.NewStyle().Foreground(warn).Render("\n" + sym.Warn + " VM IS SUSPENDED\nPress '" + m.keys.Renew.Help().Key + "' to renew and restore.")
+			}
+
+			mgmt := lipgloss.NewStyle().Foreground(accent).Render(m.status)
+			if m.busy {
+				mgmt = m.spinner.View() + " " + mgmt
 			}
 
 			details = lipgloss.JoinVertical(lipgloss.Left,
-				lipgloss.NewStyle().Foreground(grey).Render("NODE_ALIAS:    ")+lipgloss.NewStyle().Foreground(white).Render(currRow[0]),
-				lipgloss.NewStyle().Foreground(grey).Render("CURRENT_IP:    ")+lipgloss.NewStyle().Foreground(white).Render(currRow[2]),
-				lipgloss.NewStyle().Foreground(grey).Render("LEASE_TTL:     ")+lipgloss.NewStyle().Foreground(white).Render(currRow[3]),
-				lipgloss.NewStyle().Foreground(grey).Render("GEO_REGION:    ")+lipgloss.NewStyle().Foreground(white).Render(currRow[4]),
+				lipgloss.NewStyle().Foreground(muted).Render("NODE_ALIAS:    ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colAlias]),
+				lipgloss.NewStyle().Foreground(muted).Render("CURRENT_IP:    ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colIP]),
+				lipgloss.NewStyle().Foreground(muted).Render("LEASE_TTL:     ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colTTL]),
+				lipgloss.NewStyle().Foreground(muted).Render("GEO_REGION:    ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colRegion]),
+				lipgloss.NewStyle().Foreground(muted).Render("LIFETIME_COST: ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colCost]),
+				lipgloss.NewStyle().Foreground(muted).Render("NODE_AGE:      ")+lipgloss.NewStyle().Foreground(bright).Render(currRow[colAge]),
 				"",
-				lipgloss.NewStyle().Foreground(grey).Render("STATUS:        ")+lipgloss.NewStyle().Foreground(stColor).Bold(true).Render(currRow[1]),
-				lipgloss.NewStyle().Foreground(grey).Render("MGMT:          ")+lipgloss.NewStyle().Foreground(red).Render(m.status),
+				lipgloss.NewStyle().Foreground(muted).Render("STATUS:        ")+lipgloss.NewStyle().Foreground(stColor).Bold(true).Render(currRow[colStatus]),
+				lipgloss.NewStyle().Foreground(muted).Render("MGMT:          ")+mgmt,
 				hintText,
 			)
+			if m.telemetry.enabled {
+				details = lipgloss.JoinVertical(lipgloss.Left, details, "", m.telemetryView())
+			}
+		}
+		if m.progress.title != "" {
+			details = m.progressView()
 		}
 		mainContent = lipgloss.JoinHorizontal(lipgloss.Top,
 			tableBox,
@@ -398,14 +655,27 @@ func (m Model) View() string {
 		)
 	}
 
+	if m.showHelp {
+		mainContent = lipgloss.NewStyle().Padding(1, 4).Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(accent).Bold(true).Render("[ KEYBINDINGS ]"),
+			"",
+			m.help.FullHelpView(m.keys.FullHelp()),
+			"",
+			helpStyle.Render("Override keys in "+config.KeymapPath()+" • any key: close"),
+		))
+	} else if m.logs.visible {
+		mainContent = lipgloss.JoinVertical(lipgloss.Left, mainContent, m.logView())
+	}
+
 	footer := lipgloss.JoinVertical(lipgloss.Left,
-		helpStyle.Render(fmt.Sprintf(" %dx%d • n: new node • ctrl+r: sync • s: ssh • d: delete • q: quit", m.width, m.height)),
-		lipgloss.NewStyle().Foreground(red).Render(" [!] AUTO-SYNC ACTIVE ($0.001/refresh)"),
+		" "+m.help.ShortHelpView(m.keys.ShortHelp()),
+		lipgloss.NewStyle().Foreground(accent).Render(m.syncStatusView()),
 	)
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		header,
 		wallet,
+		m.widgetsView(),
 		"\n",
 		mainContent,
 		"\n",
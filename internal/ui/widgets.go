@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x402-Systems/entropy/internal/api"
+	"github.com/x402-Systems/entropy/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zalando/go-keyring"
+)
+
+// widgetsEvery is deliberately slow: balances and /stats are free reads and
+// refresh on their own schedule, never alongside the paid /list sync
+const widgetsEvery = 2 * time.Minute
+
+// widget is one header reading. An empty value with no error means the
+// identity is not linked, so the widget is hidden.
+type widget struct {
+	value string
+	err   error
+}
+
+type widgetsMsg struct {
+	usdc, xmr, stats widget
+	at               time.Time
+}
+
+// fetchWidgets reads the balances and orchestrator status in parallel
+func fetchWidgets(evmRPC, usdcContract string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		msg := widgetsMsg{at: time.Now()}
+		var wg sync.WaitGroup
+		if addr, err := keyring.Get(config.KeyringService, config.UserAccount+"-addr"); err == nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bal, err := api.USDCBalance(ctx, evmRPC, usdcContract, addr)
+				msg.usdc = widget{value: fmt.Sprintf("%.2f USDC", bal), err: err}
+			}()
+		}
+		if _, err := keyring.Get(config.KeyringService, config.UserAccount+"-xmr-addr"); err == nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bal, err := api.MoneroBalance(ctx, api.MoneroRPCURL())
+				msg.xmr = widget{value: fmt.Sprintf("%.4f XMR", bal), err: err}
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats, err := api.FetchStats(ctx)
+			if err == nil {
+				msg.stats.value = fmt.Sprintf("%v (%v VMs, CPU %v%%)", stats["status"], stats["active_vms"], stats["cpu_usage"])
+			}
+			msg.stats.err = err
+		}()
+		wg.Wait()
+		return msg
+	}
+}
+
+func (m Model) scheduleWidgets() tea.Cmd {
+	return tea.Tick(widgetsEvery, func(time.Time) tea.Msg {
+		return fetchWidgets(m.evmRPC, m.usdcContract)()
+	})
+}
+
+func (m Model) widgetsView() string {
+	if m.widgets.at.IsZero() {
+		return helpStyle.Render(" WALLET: reading balances...")
+	}
+	label := func(s string) string { return lipgloss.NewStyle().Foreground(muted).Render(s) }
+
+	var parts []string
+	for _, w := range []struct {
+		name string
+		widget
+	}{{"USDC", m.widgets.usdc}, {"XMR", m.widgets.xmr}, {"ORCHESTRATOR", m.widgets.stats}} {
+		switch {
+		case w.err != nil:
+			parts = append(parts, label(w.name+": ")+lipgloss.NewStyle().Foreground(warn).Render(sym.Warn+" unavailable"))
+		case w.value != "":
+			parts = append(parts, label(w.name+": ")+lipgloss.NewStyle().Foreground(bright).Render(w.value))
+		}
+	}
+	parts = append(parts, label("@ "+m.widgets.at.Format("15:04")))
+	return " " + strings.Join(parts, "  ")
+}
@@ -1,14 +1,29 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/x402-Systems/entropy/cmd"
 	"github.com/x402-Systems/entropy/internal/db"
-	"log"
+	"github.com/x402-Systems/entropy/internal/eventlog"
 )
 
 func main() {
-	if err := db.Init(); err != nil {
-		log.Fatalf("CRITICAL: Failed to initialize local database: %v", err)
+	// A missing log file only disables logging; it never blocks a command
+	if logFile, err := eventlog.Init(); err == nil {
+		defer logFile.Close()
+	}
+
+	// `entropy db migrate` migrates (or only reports) by itself
+	open := db.Init
+	if cmd.ManagesSchema(os.Args[1:]) {
+		open = db.Open
+	}
+	if err := open(); err != nil {
+		// The standard logger now feeds the event log, so report on stderr directly
+		fmt.Fprintf(os.Stderr, "CRITICAL: Failed to initialize local database: %v\n", err)
+		os.Exit(1)
 	}
 
 	cmd.Execute()